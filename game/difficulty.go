@@ -0,0 +1,141 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A preset that decides which words are fair game and how many wrong
+// guesses the player gets
+type Difficulty struct {
+	Name        string
+	Description string
+	// Word length bounds, in letters. Zero means no bound.
+	MinLength int
+	MaxLength int
+	// Bounds on the Commonness of a word's letters. Zero means no bound.
+	MinCommonness float64
+	MaxCommonness float64
+	// How many wrong guesses end the game
+	MaxMisses int
+	// Scores are multiplied by this. Zero counts as 1.
+	Multiplier float64
+}
+
+var (
+	Easy = Difficulty{
+		Name:          "Easy",
+		Description:   "Medium length words full of common letters",
+		MinLength:     5,
+		MaxLength:     9,
+		MinCommonness: 6.0,
+		MaxMisses:     8,
+		Multiplier:    1,
+	}
+	Medium = Difficulty{
+		Name:        "Medium",
+		Description: "Any word in the dictionary",
+		MaxMisses:   8,
+		Multiplier:  1.5,
+	}
+	Hard = Difficulty{
+		Name:          "Hard",
+		Description:   "Words with uncommon letters and fewer lives",
+		MinLength:     4,
+		MaxLength:     10,
+		MaxCommonness: 6.0,
+		MaxMisses:     6,
+		Multiplier:    2,
+	}
+	Expert = Difficulty{
+		Name:          "Expert",
+		Description:   "Short words with rare letters. Good luck",
+		MinLength:     3,
+		MaxLength:     7,
+		MaxCommonness: 5.0,
+		MaxMisses:     4,
+		Multiplier:    3,
+	}
+)
+
+// All the presets, easiest first
+var Difficulties = []Difficulty{Easy, Medium, Hard, Expert}
+
+// Find a preset by name, ignoring case
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, d := range Difficulties {
+		if strings.EqualFold(d.Name, name) {
+			return d, nil
+		}
+	}
+	return Difficulty{}, fmt.Errorf("unknown difficulty %q (choose from easy, medium, hard, expert)", name)
+}
+
+func (d Difficulty) String() string {
+	return d.Name
+}
+
+// Relative frequency (percent) of each letter in English text
+var letterFrequency = map[rune]float64{
+	'E': 12.70, 'T': 9.06, 'A': 8.17, 'O': 7.51, 'I': 6.97, 'N': 6.75,
+	'S': 6.33, 'H': 6.09, 'R': 5.99, 'D': 4.25, 'L': 4.03, 'C': 2.78,
+	'U': 2.76, 'M': 2.41, 'W': 2.36, 'F': 2.23, 'G': 2.02, 'Y': 1.97,
+	'P': 1.93, 'B': 1.49, 'V': 0.98, 'K': 0.77, 'J': 0.15, 'X': 0.15,
+	'Q': 0.10, 'Z': 0.07,
+}
+
+// Score a word by the average English frequency of its distinct letters.
+// Words made of letters like E, T and A score high and are easy to guess.
+// Words leaning on J, Q and Z score low. Half the dictionary scores above 6.
+// Spaces and punctuation in phrases don't count.
+func Commonness(word string) float64 {
+	seen := make(map[rune]bool)
+	total := 0.0
+	for _, r := range strings.ToUpper(word) {
+		if seen[r] || !unicode.IsLetter(r) {
+			continue
+		}
+		seen[r] = true
+		total += letterFrequency[r]
+	}
+	if len(seen) == 0 {
+		return 0
+	}
+	return total / float64(len(seen))
+}
+
+// Does the word fit this difficulty? Spaces and punctuation in phrases
+// don't count towards the length.
+func (d Difficulty) Allows(word string) bool {
+	n := utf8.RuneCountInString(lettersOf(word))
+	if d.MinLength > 0 && n < d.MinLength {
+		return false
+	}
+	if d.MaxLength > 0 && n > d.MaxLength {
+		return false
+	}
+	if d.MinCommonness == 0 && d.MaxCommonness == 0 {
+		return true
+	}
+	c := Commonness(word)
+	if d.MinCommonness > 0 && c < d.MinCommonness {
+		return false
+	}
+	if d.MaxCommonness > 0 && c > d.MaxCommonness {
+		return false
+	}
+	return true
+}
+
+// Keep only the words that fit this difficulty
+func (d Difficulty) Filter(words []string) []string {
+	var result []string
+	for _, w := range words {
+		if d.Allows(w) {
+			result = append(result, w)
+		}
+	}
+	return result
+}
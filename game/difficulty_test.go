@@ -0,0 +1,30 @@
+package game
+
+import "testing"
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		d    Difficulty
+		word string
+		want bool
+	}{
+		{Medium, "a", true},
+		{Expert, "jazz", true},
+		{Expert, "jazzy", true},
+		// Too long for Expert, even with rare letters
+		{Expert, "quizzing", false},
+		// Seven letters, but eight characters
+		{Expert, "jazz pub", true},
+		{Expert, "jazz-pub", true},
+		{Easy, "tea", false},
+		{Easy, "state", true},
+		{Easy, "in the net", true},
+		// Common enough, but too many letters
+		{Easy, "eat the rest at a tee", false},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Allows(tt.word); got != tt.want {
+			t.Errorf("%s.Allows(%q) = %v, want %v (commonness %.2f)", tt.d.Name, tt.word, got, tt.want, Commonness(tt.word))
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Evil hangman never picks a word up front. It keeps every word that
+// fits what's been revealed so far, and on each guess it keeps the
+// biggest family of words that the guess would reveal the same way.
+// The word is only decided once there's one candidate left.
+
+// Start an adversarial game. word decides the shape of the puzzle
+// (its length and any spaces or punctuation) and the candidates are
+// every word in words with the same shape.
+func NewEvil(word string, words []string, maxMisses int) *Game {
+	g := New(word, maxMisses)
+	shape := shapeOf(string(g.word))
+	seen := make(map[string]bool)
+	for _, w := range words {
+		w = strings.ToUpper(w)
+		if !seen[w] && shapeOf(w) == shape {
+			seen[w] = true
+			g.candidates = append(g.candidates, w)
+		}
+	}
+	if !seen[string(g.word)] {
+		g.candidates = append(g.candidates, string(g.word))
+	}
+	sort.Strings(g.candidates)
+	g.word = []rune(g.candidates[0])
+	return g
+}
+
+// Letters blanked out, everything else kept
+func shapeOf(word string) string {
+	var b strings.Builder
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			b.WriteRune(Blank)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Is this an adversarial game?
+func (g *Game) Evil() bool {
+	return g.candidates != nil
+}
+
+// How many words are still possible. Always 1 for a normal game.
+func (g *Game) Candidates() int {
+	if g.candidates == nil {
+		return 1
+	}
+	return len(g.candidates)
+}
+
+// Where a letter shows up in a word, as a key for grouping words
+func positionsKey(word string, letter rune) string {
+	var b strings.Builder
+	for i, r := range []rune(word) {
+		if r == letter {
+			b.WriteString(string(rune('a' + i)))
+		}
+	}
+	return b.String()
+}
+
+// Split the candidates by where letter would show up and keep the biggest
+// family. Ties go to the family that reveals least, so the player is
+// told "no" whenever that's just as good.
+func (g *Game) dodge(letter rune) {
+	families := make(map[string][]string)
+	for _, c := range g.candidates {
+		key := positionsKey(c, letter)
+		families[key] = append(families[key], c)
+	}
+
+	best := ""
+	first := true
+	for key, family := range families {
+		if first {
+			best, first = key, false
+			continue
+		}
+		size, bestSize := len(family), len(families[best])
+		switch {
+		case size > bestSize:
+			best = key
+		case size == bestSize && len(key) < len(best):
+			best = key
+		case size == bestSize && len(key) == len(best) && key < best:
+			best = key
+		}
+	}
+	g.candidates = families[best]
+	g.word = []rune(g.candidates[0])
+}
+
+// Drop a solve attempt from the candidates, unless it's the only one left
+func (g *Game) dodgeSolve(letters string) {
+	if len(g.candidates) <= 1 {
+		return
+	}
+	var rest []string
+	for _, c := range g.candidates {
+		if lettersOf(c) != letters {
+			rest = append(rest, c)
+		}
+	}
+	if len(rest) == 0 {
+		return
+	}
+	g.candidates = rest
+	g.word = []rune(g.candidates[0])
+}
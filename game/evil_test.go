@@ -0,0 +1,141 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDodge(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		guess string
+		// The family that should be kept
+		want      []string
+		positions []int
+	}{
+		{
+			name:  "bigger family without the letter",
+			words: []string{"BEAR", "DEAR", "FEAR", "BOLT", "COLT", "DOLT", "MOLT"},
+			guess: "E",
+			want:  []string{"BOLT", "COLT", "DOLT", "MOLT"},
+		},
+		{
+			name:      "bigger family with the letter",
+			words:     []string{"BEAR", "DEAR", "FEAR", "GEAR", "BOLT", "COLT"},
+			guess:     "E",
+			want:      []string{"BEAR", "DEAR", "FEAR", "GEAR"},
+			positions: []int{1},
+		},
+		{
+			name:  "a tie goes to a miss",
+			words: []string{"BEAR", "DEAR", "BOLT", "COLT"},
+			guess: "E",
+			want:  []string{"BOLT", "COLT"},
+		},
+		{
+			name:      "a tie between hits goes to the smaller reveal",
+			words:     []string{"BEAR", "DEAR", "EYES", "EVEN"},
+			guess:     "E",
+			want:      []string{"BEAR", "DEAR"},
+			positions: []int{1},
+		},
+		{
+			name:      "a tie between the same sized reveals goes to the earlier one",
+			words:     []string{"BEAR", "DEAR", "ABLE", "AXLE"},
+			guess:     "E",
+			want:      []string{"BEAR", "DEAR"},
+			positions: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewEvil(tt.words[0], tt.words, 8)
+			result, err := g.Guess(tt.guess)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(g.candidates, tt.want) {
+				t.Errorf("candidates = %v, want %v", g.candidates, tt.want)
+			}
+			if !reflect.DeepEqual(result.Positions, tt.positions) {
+				t.Errorf("positions = %v, want %v", result.Positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestDodgeSolve(t *testing.T) {
+	g := NewEvil("BEAR", []string{"BEAR", "DEAR", "FEAR"}, 8)
+	g.Guess("E")
+	g.Guess("A")
+	g.Guess("R")
+
+	// A solve attempt is dodged while there's another word to hide behind
+	if _, err := g.Solve("bear"); err != nil {
+		t.Fatal(err)
+	}
+	if g.Won() || g.Candidates() != 2 {
+		t.Fatalf("solving BEAR: won = %v, %d candidates left", g.Won(), g.Candidates())
+	}
+	g.Solve("dear")
+	if g.Won() || g.Word() != "FEAR" {
+		t.Fatalf("solving DEAR: won = %v, word = %s", g.Won(), g.Word())
+	}
+	// But not once there's only one
+	g.Solve("fear")
+	if !g.Won() {
+		t.Fatal("the last word left can be solved")
+	}
+}
+
+// An evil game's word, once it's over, agrees with everything the
+// player was told along the way. Replays rely on this: a normal game
+// for that word plays back the same.
+func TestEvilWordAgreesWithGuesses(t *testing.T) {
+	words := []string{
+		"BEAR", "DEAR", "FEAR", "GEAR", "HEAR", "NEAR", "PEAR", "REAR", "SEAR", "TEAR", "WEAR", "YEAR",
+		"BOLT", "COLT", "DOLT", "JOLT", "MOLT", "VOLT", "BEAT", "HEAT", "MEAT", "NEAT", "PEAT", "SEAT",
+		"ABLE", "AXLE", "EYES", "EVEN", "OBOE", "ZERO", "QUIZ", "JINX",
+	}
+	alphabet := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		evil := NewEvil(words[rng.Intn(len(words))], words, 6)
+		var results []Result
+		var moves []string
+		for _, j := range rng.Perm(len(alphabet)) {
+			if evil.Status() != Playing {
+				break
+			}
+			guess := string(alphabet[j])
+			var result Result
+			if rng.Intn(8) == 0 && evil.Candidates() > 0 {
+				// Now and then, try to solve
+				guess = words[rng.Intn(len(words))]
+				result, _ = evil.Solve(guess)
+			} else {
+				result, _ = evil.Guess(guess)
+			}
+			moves = append(moves, guess)
+			results = append(results, result)
+		}
+
+		plain := New(evil.Word(), 6)
+		for k, guess := range moves {
+			var result Result
+			if len(guess) > 1 {
+				result, _ = plain.Solve(guess)
+			} else {
+				result, _ = plain.Guess(guess)
+			}
+			if !reflect.DeepEqual(result.Positions, results[k].Positions) {
+				t.Fatalf("game %d, %s: evil said %v, %s says %v", i, guess, results[k].Positions, evil.Word(), result.Positions)
+			}
+		}
+		if plain.Pattern() != evil.Pattern() || plain.Misses() != evil.Misses() || plain.Status() != evil.Status() {
+			t.Fatalf("game %d: evil ended %+v, %s plays back %+v", i, evil.State(), evil.Word(), plain.State())
+		}
+	}
+}
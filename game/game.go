@@ -0,0 +1,334 @@
+// Package game implements the rules of hangman without any user interface.
+//
+// A Game holds the secret word, the letters guessed so far, and how many
+// wrong guesses the player has left. Frontends (the TUI, bots, tests) drive
+// it by calling Guess and reading back State.
+package game
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// What a letter looks like in a Pattern before it has been guessed
+const Blank = '_'
+
+// The number of wrong guesses allowed unless told otherwise.
+// One for each frame of the hangman graphic.
+const DefaultMaxMisses = 8
+
+// How many misses a wrong solve attempt costs unless told otherwise
+const DefaultSolvePenalty = 2
+
+var (
+	// The guess wasn't a single letter
+	ErrInvalidGuess = errors.New("guess must be a single letter")
+	// The solve attempt had no letters in it
+	ErrInvalidSolve = errors.New("solve attempt must have letters")
+	// The letter was guessed before
+	ErrAlreadyGuessed = errors.New("letter already guessed")
+	// The game is won or lost, no more guessing
+	ErrGameOver = errors.New("game is over")
+)
+
+// Where a game is at
+type Status int
+
+const (
+	Playing Status = iota
+	Won
+	Lost
+)
+
+func (s Status) String() string {
+	switch s {
+	case Won:
+		return "won"
+	case Lost:
+		return "lost"
+	default:
+		return "playing"
+	}
+}
+
+// What happened on a single guess
+type Result struct {
+	// The normalized (upper case) letter that was guessed,
+	// or the whole word for a solve attempt
+	Letter string
+	// Where the letter occurs in the word. Empty on a miss.
+	// A correct solve attempt lists every position it revealed.
+	Positions []int
+	// Was this an attempt to solve the whole word?
+	Solve bool
+}
+
+// Was the letter in the word?
+func (r Result) Hit() bool {
+	return len(r.Positions) > 0
+}
+
+// A snapshot of a game, safe to hand to a frontend
+type State struct {
+	// The word with unguessed letters replaced by Blank
+	Pattern string
+	// Every accepted guess, in order
+	Guesses []string
+	// How many guesses were wrong, plus penalties for hints and solve attempts
+	Misses int
+	// How many hints were taken
+	Hints int
+	// How many wrong guesses end the game
+	MaxMisses int
+	// Playing, Won or Lost
+	Status Status
+}
+
+type Game struct {
+	// The word the player is trying to guess
+	word []rune
+	// Which letters of the word have been revealed
+	revealed []bool
+	// All the letters the player has guessed, and any solve attempts
+	guesses []string
+	misses  int
+	// The game is lost when misses reaches this
+	maxMisses int
+	// How many misses a wrong solve attempt costs
+	solvePenalty int
+	// How many hints were taken. Each one cost a miss.
+	hints int
+	// In evil mode, every word that could still be the answer.
+	// word is then just one of them. See evil.go
+	candidates []string
+}
+
+// Start a new game for word. The word is upper cased.
+// maxMisses below 1 means DefaultMaxMisses.
+//
+// The word can be a phrase: anything that isn't a letter (spaces,
+// hyphens, apostrophes, digits) is revealed from the start.
+func New(word string, maxMisses int) *Game {
+	if maxMisses < 1 {
+		maxMisses = DefaultMaxMisses
+	}
+	w := []rune(strings.ToUpper(word))
+	revealed := make([]bool, len(w))
+	for i, r := range w {
+		revealed[i] = !unicode.IsLetter(r)
+	}
+	return &Game{
+		word:         w,
+		revealed:     revealed,
+		maxMisses:    maxMisses,
+		solvePenalty: DefaultSolvePenalty,
+	}
+}
+
+// A copy of the game that can be played on without changing this one.
+// Keep one from before a guess to take the guess back.
+func (g *Game) Clone() *Game {
+	c := *g
+	c.word = append([]rune(nil), g.word...)
+	c.revealed = append([]bool(nil), g.revealed...)
+	c.guesses = append([]string(nil), g.guesses...)
+	if g.candidates != nil {
+		c.candidates = append([]string{}, g.candidates...)
+	}
+	return &c
+}
+
+// Change how many misses a wrong solve attempt costs.
+// Anything below 1 still costs 1.
+func (g *Game) SetSolvePenalty(misses int) {
+	if misses < 1 {
+		misses = 1
+	}
+	g.solvePenalty = misses
+}
+
+// Normalize a guess to a single upper case letter
+func normalize(letter string) (string, error) {
+	if utf8.RuneCountInString(letter) != 1 {
+		return "", ErrInvalidGuess
+	}
+	r, _ := utf8.DecodeRuneInString(letter)
+	if !unicode.IsLetter(r) {
+		return "", ErrInvalidGuess
+	}
+	return string(unicode.ToUpper(r)), nil
+}
+
+// Guess a letter. Invalid and repeated guesses return an error and don't
+// cost the player anything.
+func (g *Game) Guess(letter string) (Result, error) {
+	if g.Status() != Playing {
+		return Result{}, ErrGameOver
+	}
+	guess, err := normalize(letter)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, prev := range g.guesses {
+		if prev == guess {
+			return Result{Letter: guess}, ErrAlreadyGuessed
+		}
+	}
+	g.guesses = append(g.guesses, guess)
+
+	result := Result{Letter: guess}
+	r := []rune(guess)[0]
+	if g.Evil() {
+		g.dodge(r)
+	}
+	for i, c := range g.word {
+		if c == r {
+			g.revealed[i] = true
+			result.Positions = append(result.Positions, i)
+		}
+	}
+	if !result.Hit() {
+		g.misses++
+	}
+	return result, nil
+}
+
+// Just the letters, upper cased. Solve attempts are compared this way
+// so players don't have to get the punctuation of a phrase right.
+func lettersOf(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Try to solve the whole word at once. Getting it right reveals every
+// tile and wins. Getting it wrong costs the solve penalty in misses.
+// Like letters, the same wrong attempt can't be made twice.
+func (g *Game) Solve(attempt string) (Result, error) {
+	if g.Status() != Playing {
+		return Result{}, ErrGameOver
+	}
+	letters := lettersOf(attempt)
+	if letters == "" {
+		return Result{}, ErrInvalidSolve
+	}
+	guess := strings.ToUpper(strings.Join(strings.Fields(attempt), " "))
+	result := Result{Letter: guess, Solve: true}
+	for _, prev := range g.guesses {
+		if prev == guess {
+			return result, ErrAlreadyGuessed
+		}
+	}
+	g.guesses = append(g.guesses, guess)
+
+	if g.Evil() {
+		g.dodgeSolve(letters)
+	}
+	if letters != lettersOf(string(g.word)) {
+		g.misses += g.solvePenalty
+		if g.misses > g.maxMisses {
+			g.misses = g.maxMisses
+		}
+		return result, nil
+	}
+	for i := range g.word {
+		if !g.revealed[i] {
+			g.revealed[i] = true
+			result.Positions = append(result.Positions, i)
+		}
+	}
+	return result, nil
+}
+
+// Count a miss without a guess, like when the player runs out of time
+func (g *Game) TimeOut() error {
+	if g.Status() != Playing {
+		return ErrGameOver
+	}
+	g.misses++
+	return nil
+}
+
+// The secret word. Frontends should only show this once the game is over.
+// In evil mode it's whichever word the game has settled on so far.
+func (g *Game) Word() string {
+	return string(g.word)
+}
+
+// The word with unguessed letters replaced by Blank.
+// Spaces and punctuation in phrases show as themselves.
+func (g *Game) Pattern() string {
+	p := make([]rune, len(g.word))
+	for i, c := range g.word {
+		if g.revealed[i] {
+			p[i] = c
+		} else {
+			p[i] = Blank
+		}
+	}
+	return string(p)
+}
+
+// How many more wrong guesses the player can make before losing
+func (g *Game) Remaining() int {
+	return g.maxMisses - g.misses
+}
+
+func (g *Game) Misses() int {
+	return g.misses
+}
+
+// How many misses a wrong solve attempt costs
+func (g *Game) SolvePenalty() int {
+	return g.solvePenalty
+}
+
+func (g *Game) MaxMisses() int {
+	return g.maxMisses
+}
+
+// Every accepted guess, in order. Solve attempts are in here too.
+func (g *Game) Guesses() []string {
+	return append([]string(nil), g.guesses...)
+}
+
+func (g *Game) Won() bool {
+	for _, r := range g.revealed {
+		if !r {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Game) Lost() bool {
+	return !g.Won() && g.misses >= g.maxMisses
+}
+
+func (g *Game) Status() Status {
+	switch {
+	case g.Won():
+		return Won
+	case g.Lost():
+		return Lost
+	default:
+		return Playing
+	}
+}
+
+func (g *Game) State() State {
+	return State{
+		Pattern:   g.Pattern(),
+		Guesses:   g.Guesses(),
+		Misses:    g.misses,
+		Hints:     g.hints,
+		MaxMisses: g.maxMisses,
+		Status:    g.Status(),
+	}
+}
@@ -0,0 +1,223 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGuess(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxMisses int
+		guesses   []string
+		guess     string
+		want      Result
+		err       error
+		misses    int
+		pattern   string
+		wantState Status
+	}{
+		{
+			name:    "hit reveals every copy",
+			guess:   "a",
+			want:    Result{Letter: "A", Positions: []int{1, 3}},
+			pattern: "_A_A__",
+		},
+		{
+			name:    "miss costs a life",
+			guess:   "z",
+			want:    Result{Letter: "Z"},
+			misses:  1,
+			pattern: "______",
+		},
+		{
+			name:    "repeat is free",
+			guesses: []string{"A"},
+			guess:   "a",
+			want:    Result{Letter: "A"},
+			err:     ErrAlreadyGuessed,
+			pattern: "_A_A__",
+		},
+		{
+			name:    "repeated miss is free too",
+			guesses: []string{"Z"},
+			guess:   "Z",
+			want:    Result{Letter: "Z"},
+			err:     ErrAlreadyGuessed,
+			misses:  1,
+			pattern: "______",
+		},
+		{
+			name:    "more than one letter",
+			guess:   "ab",
+			err:     ErrInvalidGuess,
+			pattern: "______",
+		},
+		{
+			name:    "not a letter",
+			guess:   "1",
+			err:     ErrInvalidGuess,
+			pattern: "______",
+		},
+		{
+			name:    "nothing",
+			guess:   "",
+			err:     ErrInvalidGuess,
+			pattern: "______",
+		},
+		{
+			name:      "last letter wins",
+			guesses:   []string{"B", "A", "N"},
+			guess:     "s",
+			want:      Result{Letter: "S", Positions: []int{5}},
+			pattern:   "BANANS",
+			wantState: Won,
+		},
+		{
+			name:      "no guessing once it's won",
+			guesses:   []string{"B", "A", "N", "S"},
+			guess:     "Q",
+			err:       ErrGameOver,
+			pattern:   "BANANS",
+			wantState: Won,
+		},
+		{
+			name:      "no guessing once it's lost",
+			maxMisses: 2,
+			guesses:   []string{"Q", "W"},
+			guess:     "A",
+			err:       ErrGameOver,
+			misses:    2,
+			pattern:   "______",
+			wantState: Lost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New("bananS", tt.maxMisses)
+			for _, guess := range tt.guesses {
+				if _, err := g.Guess(guess); err != nil {
+					t.Fatalf("Guess(%q): %v", guess, err)
+				}
+			}
+			got, err := g.Guess(tt.guess)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Guess(%q) error = %v, want %v", tt.guess, err, tt.err)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Guess(%q) = %+v, want %+v", tt.guess, got, tt.want)
+			}
+			if g.Misses() != tt.misses {
+				t.Errorf("misses = %d, want %d", g.Misses(), tt.misses)
+			}
+			if g.Pattern() != tt.pattern {
+				t.Errorf("pattern = %q, want %q", g.Pattern(), tt.pattern)
+			}
+			if g.Status() != tt.wantState {
+				t.Errorf("status = %v, want %v", g.Status(), tt.wantState)
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxMisses int
+		penalty   int
+		attempts  []string
+		misses    int
+		status    Status
+		err       error
+	}{
+		{name: "right", maxMisses: 8, attempts: []string{"banana"}, status: Won},
+		{name: "wrong costs the penalty", maxMisses: 8, attempts: []string{"banane"}, misses: DefaultSolvePenalty},
+		{name: "custom penalty", maxMisses: 8, penalty: 3, attempts: []string{"banane"}, misses: 3},
+		{name: "penalty stops at max misses", maxMisses: 3, penalty: 5, attempts: []string{"banane"}, misses: 3, status: Lost},
+		{name: "same wrong attempt twice is free", maxMisses: 8, attempts: []string{"banane", "BANANE"}, misses: DefaultSolvePenalty, err: ErrAlreadyGuessed},
+		{name: "no letters", maxMisses: 8, attempts: []string{"!!"}, err: ErrInvalidSolve},
+		{name: "over", maxMisses: 2, attempts: []string{"banane", "banana"}, misses: 2, status: Lost, err: ErrGameOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New("banana", tt.maxMisses)
+			if tt.penalty > 0 {
+				g.SetSolvePenalty(tt.penalty)
+			}
+			var err error
+			for _, a := range tt.attempts {
+				_, err = g.Solve(a)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if g.Misses() != tt.misses {
+				t.Errorf("misses = %d, want %d", g.Misses(), tt.misses)
+			}
+			if g.Status() != tt.status {
+				t.Errorf("status = %v, want %v", g.Status(), tt.status)
+			}
+		})
+	}
+}
+
+func TestPhrasePreReveal(t *testing.T) {
+	tests := []struct {
+		word    string
+		pattern string
+	}{
+		{"banana", "______"},
+		{"ice cream", "___ _____"},
+		{"rock 'n' roll", "____ '_' ____"},
+		{"catch-22", "_____-22"},
+	}
+	for _, tt := range tests {
+		g := New(tt.word, 0)
+		if g.Pattern() != tt.pattern {
+			t.Errorf("New(%q).Pattern() = %q, want %q", tt.word, g.Pattern(), tt.pattern)
+		}
+		if g.MaxMisses() != DefaultMaxMisses {
+			t.Errorf("New(%q, 0).MaxMisses() = %d, want %d", tt.word, g.MaxMisses(), DefaultMaxMisses)
+		}
+	}
+
+	// Punctuation doesn't have to be typed to solve a phrase
+	g := New("rock 'n' roll", 0)
+	if _, err := g.Solve("rock n roll"); err != nil || !g.Won() {
+		t.Errorf("Solve without punctuation: err = %v, won = %v", err, g.Won())
+	}
+}
+
+func TestClone(t *testing.T) {
+	g := New("banana", 8)
+	g.Guess("A")
+	c := g.Clone()
+
+	c.Guess("N")
+	c.Guess("Z")
+	if g.Pattern() != "_A_A_A" || g.Misses() != 0 || len(g.Guesses()) != 1 {
+		t.Errorf("playing the clone changed the original: %+v", g.State())
+	}
+	if c.Pattern() != "_ANANA" || c.Misses() != 1 {
+		t.Errorf("clone = %+v", c.State())
+	}
+
+	// The original goes its own way too
+	g.Guess("B")
+	if c.Pattern() != "_ANANA" {
+		t.Errorf("playing the original changed the clone: %q", c.Pattern())
+	}
+
+	e := NewEvil("cat", []string{"cat", "cot", "cut", "dog"}, 8)
+	before := e.Candidates()
+	ec := e.Clone()
+	ec.Guess("C")
+	ec.Guess("A")
+	if e.Candidates() != before || ec.Candidates() == before || !e.Evil() || !ec.Evil() {
+		t.Errorf("evil clone shares candidates: %d left in the original", e.Candidates())
+	}
+	if New("cat", 8).Clone().Evil() {
+		t.Error("clone of a normal game is evil")
+	}
+}
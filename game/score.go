@@ -0,0 +1,66 @@
+package game
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	// Points for each letter uncovered on the board
+	PointsPerTile = 10
+	// Bonus for each life left after a win
+	PointsPerLife = 25
+	// What each hint costs
+	HintPenalty = 15
+	// Each win in a row adds this much to the streak multiplier...
+	StreakStep = 0.25
+	// ...up to this much
+	MaxStreakMultiplier = 3.0
+)
+
+// How a game's points add up
+type Score struct {
+	// Points from uncovered letters
+	Tiles int
+	// Bonus for lives left, only for a win
+	Lives int
+	// Points taken off for hints
+	Hints int
+	// What the points are multiplied by
+	Streak     float64
+	Difficulty float64
+	// Everything put together
+	Total int
+}
+
+// Score a game, finished or not. streak is how many games in a row the
+// player won before this one.
+func ScoreGame(s State, d Difficulty, streak int) Score {
+	score := Score{
+		Hints:      s.Hints * HintPenalty,
+		Streak:     StreakMultiplier(streak),
+		Difficulty: d.Multiplier,
+	}
+	if score.Difficulty == 0 {
+		score.Difficulty = 1
+	}
+	for _, r := range s.Pattern {
+		if r != Blank && unicode.IsLetter(r) {
+			score.Tiles += PointsPerTile
+		}
+	}
+	if s.Status == Won {
+		score.Lives = (s.MaxMisses - s.Misses) * PointsPerLife
+	}
+	base := score.Tiles + score.Lives - score.Hints
+	if base < 0 {
+		base = 0
+	}
+	score.Total = int(math.Round(float64(base) * score.Streak * score.Difficulty))
+	return score
+}
+
+// The multiplier for winning streak games in a row
+func StreakMultiplier(streak int) float64 {
+	return math.Min(1+StreakStep*float64(streak), MaxStreakMultiplier)
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The solver works from what a player can see: the pattern on the board
+// and the letters already guessed. It narrows a word list down to the
+// words that fit and ranks the unguessed letters by how much guessing
+// them would tell you.
+
+// Asking for a hint with one life left would be the end of you
+var ErrNoHintsLeft = errors.New("not enough lives left for a hint")
+
+// How one letter would do as the next guess
+type LetterScore struct {
+	Letter string
+	// How many candidates have the letter
+	Count int
+	// Expected information from guessing it, in bits. Higher is better:
+	// the answer splits the candidates into more, smaller groups.
+	Entropy float64
+}
+
+// Every word in words that could be behind pattern. Blanks in the pattern
+// are Blank, and wrong holds letters known not to be in the word.
+// A blank can't be a letter that's already showing, since guessing a
+// letter reveals every copy of it.
+func Candidates(pattern string, wrong string, words []string) []string {
+	p := []rune(strings.ToUpper(pattern))
+	excluded := make(map[rune]bool)
+	for _, r := range strings.ToUpper(wrong) {
+		excluded[r] = true
+	}
+	for _, r := range p {
+		if r != Blank {
+			excluded[r] = true
+		}
+	}
+
+	var result []string
+	for _, w := range words {
+		if fits(p, w, excluded) {
+			result = append(result, strings.ToUpper(w))
+		}
+	}
+	return result
+}
+
+func fits(pattern []rune, word string, excluded map[rune]bool) bool {
+	i := 0
+	for _, r := range word {
+		if i == len(pattern) {
+			return false
+		}
+		r = unicode.ToUpper(r)
+		switch {
+		case pattern[i] == Blank:
+			if !unicode.IsLetter(r) || excluded[r] {
+				return false
+			}
+		case r != pattern[i]:
+			return false
+		}
+		i++
+	}
+	return i == len(pattern)
+}
+
+// Rank every letter not in guessed by expected information, best first.
+// Letters no candidate has are left out.
+func RankLetters(candidates []string, guessed string) []LetterScore {
+	skip := make(map[rune]bool)
+	for _, r := range strings.ToUpper(guessed) {
+		skip[r] = true
+	}
+
+	// Group the candidates by where each letter shows up in them
+	families := make(map[rune]map[uint64]int)
+	for _, c := range candidates {
+		masks := positionMasks(c)
+		for r, mask := range masks {
+			if skip[r] {
+				continue
+			}
+			if families[r] == nil {
+				families[r] = make(map[uint64]int)
+			}
+			families[r][mask]++
+		}
+	}
+
+	total := float64(len(candidates))
+	var scores []LetterScore
+	for r, fams := range families {
+		score := LetterScore{Letter: string(r)}
+		missing := len(candidates)
+		for _, n := range fams {
+			score.Count += n
+			missing -= n
+			score.Entropy -= plogp(float64(n) / total)
+		}
+		score.Entropy -= plogp(float64(missing) / total)
+		scores = append(scores, score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.Entropy != b.Entropy {
+			return a.Entropy > b.Entropy
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Letter < b.Letter
+	})
+	return scores
+}
+
+// For each letter in word, a bit set for every position it's at.
+// Positions past 64 wrap around, which only blurs absurdly long phrases.
+func positionMasks(word string) map[rune]uint64 {
+	masks := make(map[rune]uint64, len(word))
+	i := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			masks[r] |= 1 << (i % 64)
+		}
+		i++
+	}
+	return masks
+}
+
+func plogp(p float64) float64 {
+	if p == 0 {
+		return 0
+	}
+	return p * math.Log2(p)
+}
+
+// Suggest the next letter using words as the dictionary.
+// A hint costs a miss, so it's refused with only one life left.
+func (g *Game) Hint(words []string) (LetterScore, error) {
+	if g.Status() != Playing {
+		return LetterScore{}, ErrGameOver
+	}
+	if g.Remaining() <= 1 {
+		return LetterScore{}, ErrNoHintsLeft
+	}
+
+	guessed, wrong := splitGuesses(g.State())
+	ranked := RankLetters(Candidates(g.Pattern(), wrong, words), guessed)
+	var best LetterScore
+	if len(ranked) > 0 {
+		best = ranked[0]
+	} else {
+		// The word isn't in the list. Fall back to the first letter
+		// still hidden so the hint is at least true.
+		for i, r := range g.word {
+			if !g.revealed[i] {
+				best = LetterScore{Letter: string(r)}
+				break
+			}
+		}
+	}
+	g.misses++
+	g.hints++
+	return best, nil
+}
+
+// How many hints were taken
+func (g *Game) Hints() int {
+	return g.hints
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var solverWords = []string{
+	"hangman", "landman", "caveman", "batsman", "bagman", "bahamas",
+	"baa", "bar", "bat",
+	"rock 'n' roll", "rock n roll", "rick 'n' rolls", "jack-o-lantern",
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wrong   string
+		want    []string
+	}{
+		{
+			name:    "pattern and wrong letters",
+			pattern: "_A__MA_",
+			wrong:   "ETS",
+			want:    []string{"HANGMAN", "LANDMAN"},
+		},
+		{
+			name:    "nothing wrong yet",
+			pattern: "_A__MA_",
+			want:    []string{"HANGMAN", "LANDMAN", "CAVEMAN", "BATSMAN"},
+		},
+		{
+			// BAHAMAS has an A where the pattern has a blank
+			name:    "a blank can't be a letter that's showing",
+			pattern: "_a_",
+			want:    []string{"BAR", "BAT"},
+		},
+		{
+			name:    "phrase punctuation has to line up",
+			pattern: "____ '_' ____",
+			want:    []string{"ROCK 'N' ROLL"},
+		},
+		{
+			name:    "phrase with hyphens",
+			pattern: "J___-_-_______",
+			want:    []string{"JACK-O-LANTERN"},
+		},
+		{
+			name:    "no blanks",
+			pattern: "BAT",
+			want:    []string{"BAT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Candidates(tt.pattern, tt.wrong, solverWords)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates(%q, %q) = %v, want %v", tt.pattern, tt.wrong, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankLetters(t *testing.T) {
+	// N is in the same places in both, so guessing it tells you
+	// nothing and it comes last. The rest split them evenly.
+	got := RankLetters([]string{"HANGMAN", "LANDMAN"}, "am")
+	var letters []string
+	for _, s := range got {
+		letters = append(letters, s.Letter)
+	}
+	if want := []string{"D", "G", "H", "L", "N"}; !reflect.DeepEqual(letters, want) {
+		t.Fatalf("RankLetters order = %v, want %v", letters, want)
+	}
+	if got[0].Entropy != 1 || got[0].Count != 1 {
+		t.Errorf("D = %+v, want 1 bit from 1 word", got[0])
+	}
+	if n := got[4]; n.Entropy != 0 || n.Count != 2 {
+		t.Errorf("N = %+v, want 0 bits from 2 words", n)
+	}
+
+	if got := RankLetters(nil, ""); len(got) != 0 {
+		t.Errorf("RankLetters with no candidates = %v", got)
+	}
+}
+
+func TestHint(t *testing.T) {
+	// _A__MA_ with E, T and S wrong leaves HANGMAN and LANDMAN
+	g := New("hangman", 6)
+	for _, guess := range []string{"A", "M", "E", "T", "S"} {
+		g.Guess(guess)
+	}
+
+	hint, err := g.Hint(solverWords)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hint.Letter != "D" {
+		t.Errorf("hint = %s, want D", hint.Letter)
+	}
+	if g.Misses() != 4 || g.Hints() != 1 {
+		t.Errorf("after a hint: %d misses, %d hints, want 4 and 1", g.Misses(), g.Hints())
+	}
+
+	// A hint never takes the last life
+	if _, err := g.Hint(solverWords); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Hint(solverWords); !errors.Is(err, ErrNoHintsLeft) {
+		t.Errorf("hint with one life left: err = %v, want %v", err, ErrNoHintsLeft)
+	}
+	if g.Misses() != 5 || g.Hints() != 2 || g.Status() != Playing {
+		t.Errorf("refused hint cost something: %d misses, %d hints, %v", g.Misses(), g.Hints(), g.Status())
+	}
+
+	// A word that isn't in the list still gets a true hint
+	g = New("zyzzyva", 8)
+	g.Guess("Z")
+	if hint, err := g.Hint(solverWords); err != nil || hint.Letter != "Y" {
+		t.Errorf("hint for a word not in the list = %q, %v, want Y", hint.Letter, err)
+	}
+
+	g = New("bat", 8)
+	g.Solve("bat")
+	if _, err := g.Hint(solverWords); !errors.Is(err, ErrGameOver) {
+		t.Errorf("hint once it's over: err = %v, want %v", err, ErrGameOver)
+	}
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A Strategy plays hangman by picking the next letter to guess.
+// It only sees what a player would: the State of the game.
+//
+// A Strategy is used for one game at a time. Make one per goroutine.
+type Strategy interface {
+	Next(s State) string
+}
+
+// The strategies NewStrategy knows about
+var StrategyNames = []string{"frequency", "entropy", "random"}
+
+// Make a strategy by name. words is the dictionary the strategy assumes
+// the answer comes from and rng is its source of randomness.
+func NewStrategy(name string, words *WordIndex, rng *rand.Rand) (Strategy, error) {
+	switch strings.ToLower(name) {
+	case "frequency":
+		return &FrequencyStrategy{tracker: tracker{index: words}}, nil
+	case "entropy":
+		return &EntropyStrategy{tracker: tracker{index: words}}, nil
+	case "random":
+		return &RandomStrategy{rng: rng}, nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q (choose from %s)", name, strings.Join(StrategyNames, ", "))
+}
+
+// Play a game to the end, one guess at a time
+func Play(g *Game, s Strategy) {
+	for g.Status() == Playing {
+		if _, err := g.Guess(s.Next(g.State())); err != nil {
+			// A confused strategy. Stop rather than loop forever.
+			return
+		}
+	}
+}
+
+// Words grouped by length so strategies don't scan the whole dictionary
+// every game. Safe to share between goroutines.
+type WordIndex struct {
+	byLength map[int][]string
+	// What each strategy decided for each board it has seen. Lots of
+	// games go through the same early boards, so this saves a lot.
+	mu        sync.Mutex
+	decisions map[string]string
+}
+
+func NewWordIndex(words []string) *WordIndex {
+	index := &WordIndex{
+		byLength:  make(map[int][]string),
+		decisions: make(map[string]string),
+	}
+	for _, w := range words {
+		w = strings.ToUpper(w)
+		n := utf8.RuneCountInString(w)
+		index.byLength[n] = append(index.byLength[n], w)
+	}
+	return index
+}
+
+// Look up what a strategy picks for a board, working it out the first
+// time. Only for strategies that always pick the same for the same board.
+func (index *WordIndex) decide(strategy string, s State, pick func() string) string {
+	guessed, _ := splitGuesses(s)
+	letters := strings.Split(guessed, "")
+	sort.Strings(letters)
+	key := strategy + "/" + s.Pattern + "/" + strings.Join(letters, "")
+
+	index.mu.Lock()
+	letter, ok := index.decisions[key]
+	index.mu.Unlock()
+	if ok {
+		return letter
+	}
+	letter = pick()
+	index.mu.Lock()
+	index.decisions[key] = letter
+	index.mu.Unlock()
+	return letter
+}
+
+// Keeps the candidates for the game in progress, narrowing them as the
+// game goes instead of starting from the whole dictionary every time.
+// Any earlier candidates are a superset of the current ones, so it's
+// fine to skip updates.
+type tracker struct {
+	index      *WordIndex
+	candidates []string
+}
+
+// Forget the last game
+func (t *tracker) reset() {
+	t.candidates = nil
+}
+
+func (t *tracker) update(s State) []string {
+	if t.candidates == nil {
+		t.candidates = t.index.byLength[utf8.RuneCountInString(s.Pattern)]
+	}
+	_, wrong := splitGuesses(s)
+	t.candidates = Candidates(s.Pattern, wrong, t.candidates)
+	return t.candidates
+}
+
+// The letters guessed so far, and the ones that missed
+func splitGuesses(s State) (guessed string, wrong string) {
+	for _, guess := range s.Guesses {
+		if utf8.RuneCountInString(guess) != 1 {
+			continue
+		}
+		guessed += guess
+		if !strings.Contains(s.Pattern, guess) {
+			wrong += guess
+		}
+	}
+	return guessed, wrong
+}
+
+// The first unguessed letter, in order of English frequency.
+// For when the candidates run out.
+func fallbackLetter(guessed string) string {
+	for _, r := range "ETAOINSHRDLCUMWFGYPBVKJXQZ" {
+		if !strings.ContainsRune(guessed, r) {
+			return string(r)
+		}
+	}
+	return "A"
+}
+
+// Guess the letter found in the most candidates
+type FrequencyStrategy struct {
+	tracker tracker
+}
+
+func (f *FrequencyStrategy) Next(s State) string {
+	if len(s.Guesses) == 0 {
+		f.tracker.reset()
+	}
+	return f.tracker.index.decide("frequency", s, func() string {
+		guessed, _ := splitGuesses(s)
+		return mostCommon(f.tracker.update(s), guessed)
+	})
+}
+
+func mostCommon(candidates []string, guessed string) string {
+	best, bestCount := "", 0
+	for _, score := range RankLetters(candidates, guessed) {
+		if score.Count > bestCount || (score.Count == bestCount && score.Letter < best) {
+			best, bestCount = score.Letter, score.Count
+		}
+	}
+	if best == "" {
+		return fallbackLetter(guessed)
+	}
+	return best
+}
+
+// Guess the letter with the most expected information, like the hints do
+type EntropyStrategy struct {
+	tracker tracker
+}
+
+func (e *EntropyStrategy) Next(s State) string {
+	if len(s.Guesses) == 0 {
+		e.tracker.reset()
+	}
+	return e.tracker.index.decide("entropy", s, func() string {
+		guessed, _ := splitGuesses(s)
+		ranked := RankLetters(e.tracker.update(s), guessed)
+		if len(ranked) == 0 {
+			return fallbackLetter(guessed)
+		}
+		return ranked[0].Letter
+	})
+}
+
+// Guess any letter not guessed yet. A baseline to beat.
+type RandomStrategy struct {
+	rng *rand.Rand
+}
+
+func (r *RandomStrategy) Next(s State) string {
+	guessed, _ := splitGuesses(s)
+	var left []rune
+	for c := 'A'; c <= 'Z'; c++ {
+		if !strings.ContainsRune(guessed, c) {
+			left = append(left, c)
+		}
+	}
+	if len(left) == 0 {
+		return "A"
+	}
+	return string(left[r.rng.Intn(len(left))])
+}
@@ -0,0 +1,344 @@
+package internal
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	API stuff
+//
+// The `hangman api` command: play hangman over HTTP with JSON.
+//
+//	POST /games               start a game: {"difficulty","category","seed"}, all optional
+//	GET  /games/{id}          see how a game is going
+//	POST /games/{id}/guesses  guess a letter, or solve: {"guess":"E"}
+//
+// Every response is a game, or {"error": "..."} with a 4xx status.
+// ******************************************************************
+type APIOptions struct {
+	// Where games are kept. Nil means in memory for TTL.
+	Store GameStore
+	// How long an untouched game is kept in memory
+	TTL time.Duration
+	// Word packs that can be asked for by category
+	Packs []WordPack
+	// Where to log requests. Nil means don't.
+	Log *log.Logger
+}
+
+// How to start a game. Everything is optional.
+type NewGameRequest struct {
+	Difficulty string `json:"difficulty"`
+	Category   string `json:"category"`
+	// Zero picks a random seed
+	Seed int64 `json:"seed"`
+}
+
+type GuessRequest struct {
+	Guess string `json:"guess"`
+}
+
+// A game as the API shows it
+type GameResponse struct {
+	ID string `json:"id"`
+	// The board, with _ for letters not found yet
+	Board     string   `json:"board"`
+	Misses    int      `json:"misses"`
+	MaxMisses int      `json:"max_misses"`
+	Guessed   []string `json:"guessed"`
+	// playing, won or lost
+	Status     string `json:"status"`
+	Difficulty string `json:"difficulty"`
+	Category   string `json:"category,omitempty"`
+	Seed       int64  `json:"seed"`
+	// The word, only once the game is over
+	Word string `json:"word,omitempty"`
+	// What the guess just made did. Only in answer to a guess.
+	Result *GuessResult `json:"result,omitempty"`
+}
+
+type GuessResult struct {
+	Guess string `json:"guess"`
+	Hit   bool   `json:"hit"`
+	// Where the guess uncovered letters
+	Positions []int `json:"positions"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type API struct {
+	store GameStore
+	packs []WordPack
+	log   *log.Logger
+	// Guesses on the same game mustn't cross. One at a time is plenty.
+	mu sync.Mutex
+}
+
+func NewAPI(opts APIOptions) *API {
+	store := opts.Store
+	if store == nil {
+		store = NewMemoryStore(opts.TTL)
+	}
+	logger := opts.Log
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	return &API{store: store, packs: opts.Packs, log: logger}
+}
+
+// Route requests to the right handler
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.log.Printf("%s %s", r.Method, r.URL.Path)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "games":
+		a.allow(w, r, http.MethodPost, a.createGame)
+	case len(parts) == 2 && parts[0] == "games":
+		a.allow(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			a.getGame(w, parts[1])
+		})
+	case len(parts) == 3 && parts[0] == "games" && parts[2] == "guesses":
+		a.allow(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			a.guess(w, r, parts[1])
+		})
+	default:
+		writeJSON(w, http.StatusNotFound, apiError{"not found"})
+	}
+}
+
+// Only let one method through
+func (a *API) allow(w http.ResponseWriter, r *http.Request, method string, handler http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{fmt.Sprintf("use %s", method)})
+		return
+	}
+	handler(w, r)
+}
+
+// POST /games
+func (a *API) createGame(w http.ResponseWriter, r *http.Request) {
+	var req NewGameRequest
+	if err := readJSON(r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+		return
+	}
+
+	d := game.Medium
+	if req.Difficulty != "" {
+		var err error
+		if d, err = game.ParseDifficulty(req.Difficulty); err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+			return
+		}
+	}
+	words := dictionary
+	category := ""
+	if req.Category != "" {
+		pack, err := FindPack(a.packs, req.Category)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+			return
+		}
+		words = pack.Words
+		category = pack.Name
+	}
+	seed := req.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	pool := wordPool(words, d)
+	rng := rand.New(rand.NewSource(seed))
+
+	now := time.Now()
+	stored := StoredGame{
+		ID:         newGameID(),
+		Word:       pool[rng.Intn(len(pool))],
+		MaxMisses:  d.MaxMisses,
+		Guesses:    []string{},
+		Difficulty: d.Name,
+		Category:   category,
+		Seed:       seed,
+		Created:    now,
+		Updated:    now,
+	}
+	if err := a.store.Put(stored); err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+		return
+	}
+	g, _ := stored.Game()
+	w.Header().Set("Location", "/games/"+stored.ID)
+	writeJSON(w, http.StatusCreated, gameResponse(stored, g))
+}
+
+// GET /games/{id}
+func (a *API) getGame(w http.ResponseWriter, id string) {
+	stored, g, ok := a.load(w, id)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, gameResponse(stored, g))
+}
+
+// POST /games/{id}/guesses
+func (a *API) guess(w http.ResponseWriter, r *http.Request, id string) {
+	var req GuessRequest
+	if err := readJSON(r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stored, g, ok := a.load(w, id)
+	if !ok {
+		return
+	}
+
+	var result game.Result
+	var err error
+	guess := strings.TrimSpace(req.Guess)
+	if len([]rune(guess)) > 1 {
+		result, err = g.Solve(guess)
+	} else {
+		result, err = g.Guess(guess)
+	}
+	switch {
+	case errors.Is(err, game.ErrAlreadyGuessed), errors.Is(err, game.ErrGameOver):
+		writeJSON(w, http.StatusConflict, apiError{err.Error()})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+		return
+	}
+
+	stored.Guesses = g.Guesses()
+	stored.Updated = time.Now()
+	if err := a.store.Put(stored); err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+		return
+	}
+	resp := gameResponse(stored, g)
+	resp.Result = &GuessResult{Guess: result.Letter, Hit: result.Hit(), Positions: result.Positions}
+	if resp.Result.Positions == nil {
+		resp.Result.Positions = []int{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Look a game up and rebuild it, or write why not
+func (a *API) load(w http.ResponseWriter, id string) (StoredGame, *game.Game, bool) {
+	stored, err := a.store.Get(id)
+	if errors.Is(err, ErrGameNotFound) {
+		writeJSON(w, http.StatusNotFound, apiError{err.Error()})
+		return stored, nil, false
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+		return stored, nil, false
+	}
+	g, err := stored.Game()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+		return stored, nil, false
+	}
+	return stored, g, true
+}
+
+// What the API shows of a game
+func gameResponse(stored StoredGame, g *game.Game) GameResponse {
+	resp := GameResponse{
+		ID:         stored.ID,
+		Board:      g.Pattern(),
+		Misses:     g.Misses(),
+		MaxMisses:  g.MaxMisses(),
+		Guessed:    g.Guesses(),
+		Status:     g.Status().String(),
+		Difficulty: stored.Difficulty,
+		Category:   stored.Category,
+		Seed:       stored.Seed,
+	}
+	if resp.Guessed == nil {
+		resp.Guessed = []string{}
+	}
+	if g.Status() != game.Playing {
+		resp.Word = g.Word()
+	}
+	return resp
+}
+
+// A random ID that's hard to guess
+func newGameID() string {
+	b := make([]byte, 8)
+	crand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Decode a JSON body. An empty body is fine and leaves v alone.
+func readJSON(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("bad JSON: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// The most often a memory store is cleared out, however short the TTL
+const minEvictInterval = time.Second
+
+// Serve the API on addr until something goes wrong.
+// Expired games are cleared out of a memory store as it goes.
+func RunAPI(addr string, opts APIOptions) error {
+	api := NewAPI(opts)
+	if store, ok := api.store.(*MemoryStore); ok && opts.TTL > 0 {
+		interval := opts.TTL / 2
+		if interval < minEvictInterval {
+			interval = minEvictInterval
+		}
+		ticker := time.NewTicker(interval)
+		done := make(chan struct{})
+		defer func() {
+			ticker.Stop()
+			close(done)
+		}()
+		go func() {
+			for {
+				select {
+				case now := <-ticker.C:
+					if n := store.Evict(now); n > 0 {
+						api.log.Printf("forgot %d expired games", n)
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	api.log.Printf("serving the hangman API on %s", addr)
+	return http.ListenAndServe(addr, api)
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Start the API with one pack that only has one word
+func startAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	api := NewAPI(APIOptions{
+		Packs: []WordPack{{Name: "Fruit", Description: "Fruit", Words: []string{"banana"}}},
+	})
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Send body to path and decode the answer into v, checking the status
+func call(t *testing.T, srv *httptest.Server, method string, path string, body string, status int, v interface{}) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		var e apiError
+		json.NewDecoder(resp.Body).Decode(&e)
+		t.Fatalf("%s %s %s: status %d (%s), want %d", method, path, body, resp.StatusCode, e.Error, status)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func TestAPICreateGame(t *testing.T) {
+	srv := startAPI(t)
+
+	var g GameResponse
+	resp := call(t, srv, http.MethodPost, "/games", `{"category":"fruit","seed":42}`, http.StatusCreated, &g)
+	if g.Seed != 42 || g.Board != "______" || g.Category != "Fruit" || g.Difficulty != "Medium" || g.Status != "playing" {
+		t.Errorf("new game = %+v", g)
+	}
+	if g.Word != "" {
+		t.Error("the word shows before the game is over")
+	}
+	if loc := resp.Header.Get("Location"); loc != "/games/"+g.ID {
+		t.Errorf("Location = %q", loc)
+	}
+
+	var again GameResponse
+	call(t, srv, http.MethodGet, "/games/"+g.ID, "", http.StatusOK, &again)
+	if !reflect.DeepEqual(again, g) {
+		t.Errorf("GET = %+v, want %+v", again, g)
+	}
+
+	// With no body at all, everything is picked for you
+	var random GameResponse
+	call(t, srv, http.MethodPost, "/games", "", http.StatusCreated, &random)
+	if random.Seed == 0 || random.ID == g.ID || random.Difficulty != "Medium" {
+		t.Errorf("random game = %+v", random)
+	}
+
+	call(t, srv, http.MethodPost, "/games", `{"difficulty":"impossible"}`, http.StatusBadRequest, nil)
+	call(t, srv, http.MethodPost, "/games", `{"category":"cheese"}`, http.StatusBadRequest, nil)
+	call(t, srv, http.MethodPost, "/games", `{`, http.StatusBadRequest, nil)
+	call(t, srv, http.MethodGet, "/games", "", http.StatusMethodNotAllowed, nil)
+}
+
+func TestAPIGuesses(t *testing.T) {
+	srv := startAPI(t)
+	var g GameResponse
+	call(t, srv, http.MethodPost, "/games", `{"category":"fruit"}`, http.StatusCreated, &g)
+	guesses := "/games/" + g.ID + "/guesses"
+
+	call(t, srv, http.MethodPost, guesses, `{"guess":"a"}`, http.StatusOK, &g)
+	want := &GuessResult{Guess: "A", Hit: true, Positions: []int{1, 3, 5}}
+	if !reflect.DeepEqual(g.Result, want) || g.Board != "_A_A_A" {
+		t.Errorf("hit = %+v, %+v", g.Result, g)
+	}
+
+	// Spaces around a guess don't matter
+	call(t, srv, http.MethodPost, guesses, `{"guess":" z "}`, http.StatusOK, &g)
+	want = &GuessResult{Guess: "Z", Positions: []int{}}
+	if !reflect.DeepEqual(g.Result, want) || g.Misses != 1 {
+		t.Errorf("miss = %+v, %+v", g.Result, g)
+	}
+
+	call(t, srv, http.MethodPost, guesses, `{"guess":"A"}`, http.StatusConflict, nil)
+	call(t, srv, http.MethodPost, guesses, `{"guess":"7"}`, http.StatusBadRequest, nil)
+
+	call(t, srv, http.MethodPost, guesses, `{"guess":" banana "}`, http.StatusOK, &g)
+	if g.Status != "won" || g.Word != "BANANA" || !g.Result.Hit {
+		t.Errorf("solve = %+v", g)
+	}
+	if want := []string{"A", "Z", "BANANA"}; !reflect.DeepEqual(g.Guessed, want) {
+		t.Errorf("guessed = %v, want %v", g.Guessed, want)
+	}
+
+	// It's over
+	call(t, srv, http.MethodPost, guesses, `{"guess":"b"}`, http.StatusConflict, nil)
+}
+
+func TestAPINotFound(t *testing.T) {
+	srv := startAPI(t)
+	call(t, srv, http.MethodGet, "/games/nope", "", http.StatusNotFound, nil)
+	call(t, srv, http.MethodPost, "/games/nope/guesses", `{"guess":"a"}`, http.StatusNotFound, nil)
+	call(t, srv, http.MethodGet, "/elsewhere", "", http.StatusNotFound, nil)
+}
@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	Bench stuff
+//
+// The `hangman bench` command: let a strategy play lots of headless
+// games and report how it did. Handy for tuning difficulty presets.
+// ******************************************************************
+type BenchOptions struct {
+	// Which game.Strategy to play with
+	Strategy string
+	// How many games to play
+	Games int
+	// Seeds both the words picked and the random strategy
+	Seed int64
+	// How many games to play at once. Zero means one per CPU.
+	Workers int
+	// Picks the words and the number of lives
+	Difficulty game.Difficulty
+	// Words to play instead of the dictionary
+	Words []string
+	// Write every game as CSV instead of a summary table
+	CSV bool
+	// How many of the hardest words to list in the summary
+	Hardest int
+}
+
+// How a single benchmark game went
+type benchResult struct {
+	word    string
+	won     bool
+	misses  int
+	guesses []string
+}
+
+// Play the games and write a report to w
+func RunBench(w io.Writer, opts BenchOptions) error {
+	words := dictionary
+	if len(opts.Words) > 0 {
+		words = opts.Words
+	}
+	pool := wordPool(words, opts.Difficulty)
+	// The strategies know the whole list, not just the words that fit
+	index := game.NewWordIndex(words)
+
+	// Check the strategy name before starting any goroutines
+	if _, err := game.NewStrategy(opts.Strategy, index, nil); err != nil {
+		return err
+	}
+
+	// Pick every word up front so the games are the same however
+	// they end up spread across workers
+	rng := rand.New(rand.NewSource(opts.Seed))
+	picks := make([]string, opts.Games)
+	for i := range picks {
+		picks[i] = pool[rng.Intn(len(pool))]
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	start := time.Now()
+	results := make([]benchResult, opts.Games)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Each game gets its own randomness, again so results don't
+				// depend on which worker played it
+				strategy, _ := game.NewStrategy(opts.Strategy, index, rand.New(rand.NewSource(opts.Seed+int64(i))))
+				g := game.New(picks[i], opts.Difficulty.MaxMisses)
+				game.Play(g, strategy)
+				results[i] = benchResult{
+					word:    g.Word(),
+					won:     g.Won(),
+					misses:  g.Misses(),
+					guesses: g.Guesses(),
+				}
+			}
+		}()
+	}
+	for i := range picks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if opts.CSV {
+		return writeBenchCSV(w, opts, results)
+	}
+	return writeBenchTable(w, opts, results, elapsed)
+}
+
+// One row per game
+func writeBenchCSV(w io.Writer, opts BenchOptions, results []benchResult) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"strategy", "difficulty", "word", "won", "misses", "guesses"})
+	for _, r := range results {
+		out.Write([]string{
+			opts.Strategy,
+			opts.Difficulty.Name,
+			r.word,
+			strconv.FormatBool(r.won),
+			strconv.Itoa(r.misses),
+			strings.Join(r.guesses, ""),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// A summary and the words the strategy struggled with most
+func writeBenchTable(w io.Writer, opts BenchOptions, results []benchResult, elapsed time.Duration) error {
+	wins, misses := 0, 0
+	for _, r := range results {
+		if r.won {
+			wins++
+		}
+		misses += r.misses
+	}
+	games := len(results)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Strategy\t%s\n", opts.Strategy)
+	fmt.Fprintf(tw, "Difficulty\t%s (%d lives)\n", opts.Difficulty.Name, opts.Difficulty.MaxMisses)
+	fmt.Fprintf(tw, "Seed\t%d\n", opts.Seed)
+	fmt.Fprintf(tw, "Games\t%d in %s\n", games, elapsed.Round(time.Millisecond))
+	if games > 0 {
+		fmt.Fprintf(tw, "Win rate\t%.1f%%\n", float64(wins)/float64(games)*100)
+		fmt.Fprintf(tw, "Mean misses\t%.2f\n", float64(misses)/float64(games))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	hardest := hardestWords(results, opts.Hardest)
+	if len(hardest) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "\nHardest words")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Word\tPlayed\tLost\tMean misses")
+	for _, h := range hardest {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\n", h.word, h.played, h.lost, float64(h.misses)/float64(h.played))
+	}
+	return tw.Flush()
+}
+
+type wordDifficulty struct {
+	word   string
+	played int
+	lost   int
+	misses int
+}
+
+// The words lost most often, then with the most misses
+func hardestWords(results []benchResult, n int) []wordDifficulty {
+	byWord := make(map[string]*wordDifficulty)
+	for _, r := range results {
+		d, ok := byWord[r.word]
+		if !ok {
+			d = &wordDifficulty{word: r.word}
+			byWord[r.word] = d
+		}
+		d.played++
+		d.misses += r.misses
+		if !r.won {
+			d.lost++
+		}
+	}
+
+	var all []wordDifficulty
+	for _, d := range byWord {
+		all = append(all, *d)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		// Compare rates, not counts, since some words come up more often
+		lostA, lostB := float64(a.lost)/float64(a.played), float64(b.lost)/float64(b.played)
+		if lostA != lostB {
+			return lostA > lostB
+		}
+		missA, missB := float64(a.misses)/float64(a.played), float64(b.misses)/float64(b.played)
+		if missA != missB {
+			return missA > missB
+		}
+		return a.word < b.word
+	})
+	if n < len(all) {
+		all = all[:n]
+	}
+	return all
+}
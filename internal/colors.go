@@ -35,6 +35,7 @@ var DarkColors = map[string]string{
 	"Crust":       "#181926",
 }
 
+// Latte is the light flavor
 var LightColors = map[string]string{
 	"Rosewater": "#dc8a78",
 	"Flamingo":  "#dd7878",
@@ -64,12 +65,103 @@ var LightColors = map[string]string{
 	"Crust":     "#dce0e8",
 }
 
+// The full Catppuccin flavors, for the built-in themes
+var LatteColors = LightColors
+
+var FrappeColors = map[string]string{
+	"Rosewater": "#f2d5cf",
+	"Flamingo":  "#eebebe",
+	"Pink":      "#f4b8e4",
+	"Mauve":     "#ca9ee6",
+	"Red":       "#e78284",
+	"Maroon":    "#ea999c",
+	"Peach":     "#ef9f76",
+	"Yellow":    "#e5c890",
+	"Green":     "#a6d189",
+	"Teal":      "#81c8be",
+	"Sky":       "#99d1db",
+	"Sapphire":  "#85c1dc",
+	"Blue":      "#8caaee",
+	"Lavender":  "#babbf1",
+	"Text":      "#c6d0f5",
+	"Subtext1":  "#b5bfe2",
+	"Subtext0":  "#a5adce",
+	"Overlay2":  "#949cbb",
+	"Overlay1":  "#838ba7",
+	"Overlay0":  "#737994",
+	"Surface2":  "#626880",
+	"Surface1":  "#51576d",
+	"Surface0":  "#414559",
+	"Base":      "#303446",
+	"Mantle":    "#292c3c",
+	"Crust":     "#232634",
+}
+
+var MacchiatoColors = map[string]string{
+	"Rosewater": "#f4dbd6",
+	"Flamingo":  "#f0c6c6",
+	"Pink":      "#f5bde6",
+	"Mauve":     "#c6a0f6",
+	"Red":       "#ed8796",
+	"Maroon":    "#ee99a0",
+	"Peach":     "#f5a97f",
+	"Yellow":    "#eed49f",
+	"Green":     "#a6da95",
+	"Teal":      "#8bd5ca",
+	"Sky":       "#91d7e3",
+	"Sapphire":  "#7dc4e4",
+	"Blue":      "#8aadf4",
+	"Lavender":  "#b7bdf8",
+	"Text":      "#cad3f5",
+	"Subtext1":  "#b8c0e0",
+	"Subtext0":  "#a5adcb",
+	"Overlay2":  "#939ab7",
+	"Overlay1":  "#8087a2",
+	"Overlay0":  "#6e738d",
+	"Surface2":  "#5b6078",
+	"Surface1":  "#494d64",
+	"Surface0":  "#363a4f",
+	"Base":      "#24273a",
+	"Mantle":    "#1e2030",
+	"Crust":     "#181926",
+}
+
+var MochaColors = map[string]string{
+	"Rosewater": "#f5e0dc",
+	"Flamingo":  "#f2cdcd",
+	"Pink":      "#f5c2e7",
+	"Mauve":     "#cba6f7",
+	"Red":       "#f38ba8",
+	"Maroon":    "#eba0ac",
+	"Peach":     "#fab387",
+	"Yellow":    "#f9e2af",
+	"Green":     "#a6e3a1",
+	"Teal":      "#94e2d5",
+	"Sky":       "#89dceb",
+	"Sapphire":  "#74c7ec",
+	"Blue":      "#89b4fa",
+	"Lavender":  "#b4befe",
+	"Text":      "#cdd6f4",
+	"Subtext1":  "#bac2de",
+	"Subtext0":  "#a6adc8",
+	"Overlay2":  "#9399b2",
+	"Overlay1":  "#7f849c",
+	"Overlay0":  "#6c7086",
+	"Surface2":  "#585b70",
+	"Surface1":  "#45475a",
+	"Surface0":  "#313244",
+	"Base":      "#1e1e2e",
+	"Mantle":    "#181825",
+	"Crust":     "#11111b",
+}
+
 /*
 	I have no UI skills and have no idea what best practices are
 	when it comes to color code management.
 
 	Below has the appearance of a good scheme. Just change the colors here
-	and changes magically propagate where needed.
+	and changes magically propagate where needed. Themes (see theme.go)
+	overwrite these at startup.
 
 	This all falls apart when the aspiring designer realizes there is no rhyme
 	or reason for why one color is named such, then applied to some elements.
@@ -2,9 +2,12 @@ package internal
 
 import (
 	"errors"
+	"math"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/braheezy/hangman/game"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -41,15 +44,6 @@ func (s PrettyString) View() string {
 //	The top greeter
 //
 // ******************************************************
-var titleStyle = lipgloss.NewStyle().
-	Bold(true).
-	Align(lipgloss.Center).
-	Foreground(textColor).
-	Background(primaryColor).
-	PaddingLeft(2).
-	PaddingRight(2).
-	MarginBottom(1)
-
 func NewTitle() PrettyString {
 	return PrettyString{
 		text:  "Hangman\nCan you save this criminal?",
@@ -63,13 +57,27 @@ func NewTitle() PrettyString {
 //	The top greeter
 //
 // ******************************************************
-var footerStyle = lipgloss.NewStyle().
-	Foreground(primaryColor).
-	Underline(true)
+var footerText = "Press / to solve the whole word, ? for a hint (costs a life), ESC or Ctrl+C to quit."
+
+// Shown while solving
+var solveFooterText = "Press Enter to solve, ESC to go back to guessing letters."
+
+// Shown instead once the game is over
+var gameOverFooterText = "Press Enter or R to play again, D to change difficulty, C for categories, S for stats, H for high scores, ESC or Q to quit."
+
+// Shown instead when the run made the leaderboard
+var highScoreFooterText = "New high score! Press Enter to sign it and play on, or ESC or Q to sign it and quit."
+
+// Shown once the daily puzzle is over
+var dailyFooterText = "Press Y to copy your result, S for stats, Enter, ESC or Q to quit. See you tomorrow!"
+
+// Shown instead in practice mode
+var practiceFooterText = "Press Ctrl+Z to undo, / to solve the whole word, ? for a hint, ESC or Ctrl+C to quit."
+var practiceGameOverFooterText = "Press U to undo, Enter or R to play again, D to change difficulty, C for categories, ESC or Q to quit."
 
 func NewFooter() PrettyString {
 	return PrettyString{
-		text:  "Press ESC or Ctrl+C to quit.",
+		text:  footerText,
 		style: footerStyle,
 	}
 }
@@ -80,19 +88,6 @@ func NewFooter() PrettyString {
 //	This area displays game messages to the player
 //
 // ******************************************************
-var noticeStyle = lipgloss.NewStyle().
-	Bold(true).
-	Italic(true).
-	Foreground(primaryColor)
-
-var loseNoticeStyle = lipgloss.NewStyle().
-	Inherit(noticeStyle).
-	Foreground(failColor)
-
-var winNoticeStyle = lipgloss.NewStyle().
-	Inherit(noticeStyle).
-	Foreground(successColor)
-
 func NewNotice() PrettyString {
 	return PrettyString{
 		text:  "",
@@ -109,12 +104,8 @@ func NewNotice() PrettyString {
 // What to show as "blank" before the tile has been guessed
 var blankBoardTile = " "
 
-var boardTileStyle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(secondaryColor).
-	Background(strongColor).
-	Width(5).
-	Align(lipgloss.Center)
+// The space between words in a phrase. It's a gap, not a tile.
+var boardGapTile = ""
 
 // The Board is just a collection of PrettyStrings
 type Board []PrettyString
@@ -128,6 +119,42 @@ func NewBoard(n int, s lipgloss.Style) Board {
 	return Board(b)
 }
 
+// Make a new Board for a word or phrase. Letters get blank tiles.
+// Spaces become gaps and anything else (hyphens, apostrophes, digits)
+// is shown as-is since there's nothing to guess.
+func NewPuzzleBoard(puzzle string) Board {
+	var b Board
+	for _, r := range puzzle {
+		switch {
+		case unicode.IsLetter(r):
+			b = append(b, NewPrettyString(blankBoardTile, boardTileStyle))
+		case r == ' ':
+			b = append(b, NewPrettyString(boardGapTile, boardGapStyle))
+		default:
+			b = append(b, NewPrettyString(string(r), boardPunctuationStyle))
+		}
+	}
+	return b
+}
+
+// Make a new Board from a pattern, like the multiplayer server sends:
+// the letters found so far, with game.Blank for the rest
+func NewPatternBoard(pattern string) Board {
+	// Stand a letter in for each blank so it gets a tile
+	b := NewPuzzleBoard(strings.Map(func(r rune) rune {
+		if r == game.Blank {
+			return 'A'
+		}
+		return r
+	}, pattern))
+	for i, r := range []rune(pattern) {
+		if r != game.Blank && unicode.IsLetter(r) {
+			b[i].text = string(r)
+		}
+	}
+	return b
+}
+
 // Return the stylized view of the board
 // Choose how you want the Tiles to separated from each other
 func (b Board) View(sep string) string {
@@ -140,6 +167,58 @@ func (b Board) View(sep string) string {
 	return strings.Join(result, sep)
 }
 
+// Split the board into rows that fit in width, breaking between words
+// when possible. Words too long for a row of their own get chopped.
+func (b Board) Wrap(width int, sep string) []Board {
+	if width <= 0 || lipgloss.Width(b.View(sep)) <= width {
+		return []Board{b}
+	}
+
+	// Group the tiles into words
+	var words []Board
+	var word Board
+	for _, tile := range b {
+		if tile.text == boardGapTile {
+			words = append(words, word)
+			word = nil
+			continue
+		}
+		word = append(word, tile)
+	}
+	words = append(words, word)
+
+	var rows []Board
+	var row Board
+	for _, word := range words {
+		// Chop up words that could never fit
+		for lipgloss.Width(word.View(sep)) > width {
+			fit := 1
+			for fit < len(word) && lipgloss.Width(word[:fit+1].View(sep)) <= width {
+				fit++
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+				row = nil
+			}
+			rows = append(rows, word[:fit])
+			word = word[fit:]
+		}
+		candidate := word
+		if len(row) > 0 {
+			candidate = append(append(append(Board{}, row...), NewPrettyString(boardGapTile, boardGapStyle)), word...)
+		}
+		if lipgloss.Width(candidate.View(sep)) > width {
+			rows = append(rows, row)
+			candidate = word
+		}
+		row = candidate
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
 // Check if a Tile is in the Board
 func (b Board) Contains(s string) bool {
 	// Crawl through the board and see if there's a hit
@@ -164,20 +243,23 @@ type Keyboard struct {
 	// The styles to apply when the letter has been used or not
 	onStyle  lipgloss.Style
 	offStyle lipgloss.Style
+	// The letters that have been used
+	used map[string]bool
+	// The letter under the mouse, if any
+	hover string
+	// Where the keyboard's top left corner is on the screen. See Place.
+	x, y int
 }
 
-var letterOffStyle = lipgloss.NewStyle().
-	Foreground(textColor).
-	Background(primaryColor).
-	Width(3).
-	Align(lipgloss.Center)
+// The cells a letter takes up on one line
+type keyArea struct {
+	letter string
+	x, y   int
+	width  int
+}
 
-var letterOnStyle = lipgloss.NewStyle().
-	Foreground(primaryColor).
-	Background(tertiaryColor).
-	Width(3).
-	Align(lipgloss.Center).
-	Bold(true)
+// Give the keyboard some room or it crowds the hangman dude
+const keyboardMargin = 4
 
 var keyboardRows = [][]string{
 	{"Q", "W", "E", "R", "T", "Y", "U", "I", "O", "P"},
@@ -200,32 +282,107 @@ func NewKeyboard() Keyboard {
 		alphabet: alphabetTiles,
 		onStyle:  letterOnStyle,
 		offStyle: letterOffStyle,
+		used:     map[string]bool{},
 	}
 }
 
-// Call View to see stylized string representation of Keyboard
-func (keyboard Keyboard) View() string {
+// Call View to see stylized string representation of Keyboard.
+func (keyboard *Keyboard) View() string {
 	var result []string
 	// Each row is a Board, so it can be easily Viewed
 	for _, row := range keyboard.alphabet {
+		if keyboard.hover != "" && !keyboard.used[keyboard.hover] {
+			row = append(Board(nil), row...)
+			for j := range row {
+				if row[j].text == keyboard.hover {
+					row[j].style = letterHoverStyle
+				}
+			}
+		}
 		result = append(result, row.View(""))
 	}
 
 	return lipgloss.NewStyle().
-		// Give the keyboard some room or it crowds the hangman dude
-		MarginLeft(4).
+		MarginLeft(keyboardMargin).
 		Render(
 			// Combine the keyboard rows into a stack
 			lipgloss.JoinVertical(lipgloss.Center, result...),
 		)
 }
 
+// How far lipgloss.Center pushes something into the space around it.
+// JoinVertical and JoinHorizontal both work it out this way.
+func centered(space int) int {
+	return int(math.Round(float64(space) * 0.5))
+}
+
+// Say where on the screen the keyboard goes
+func (keyboard *Keyboard) Place(x, y int) {
+	keyboard.x = x
+	keyboard.y = y
+}
+
+// Where each letter is, from the keyboard's top left corner. View
+// centers each row under the widest one.
+func (keyboard *Keyboard) keyAreas() []keyArea {
+	width := 0
+	for _, row := range keyboard.alphabet {
+		if w := lipgloss.Width(row.View("")); w > width {
+			width = w
+		}
+	}
+	var keys []keyArea
+	for y, row := range keyboard.alphabet {
+		x := keyboardMargin + centered(width-lipgloss.Width(row.View("")))
+		for _, tile := range row {
+			w := lipgloss.Width(tile.View())
+			keys = append(keys, keyArea{letter: tile.text, x: x, y: y, width: w})
+			x += w
+		}
+	}
+	return keys
+}
+
+// The letter drawn at this spot on the screen, if there is one
+func (keyboard *Keyboard) At(x, y int) (string, bool) {
+	for _, key := range keyboard.keyAreas() {
+		if y == keyboard.y+key.y && x >= keyboard.x+key.x && x < keyboard.x+key.x+key.width {
+			return key.letter, true
+		}
+	}
+	return "", false
+}
+
+// Has this letter been used?
+func (keyboard *Keyboard) Used(letter string) bool {
+	return keyboard.used[letter]
+}
+
+// Highlight the letter under the mouse. Empty means none.
+func (keyboard *Keyboard) Hover(letter string) {
+	keyboard.hover = letter
+}
+
 // Find this letter in the Keyboard struct and flip it's style between off/on
 func (letters *Keyboard) FlipOn(letter string) {
 	for i, row := range letters.alphabet {
 		for j, tile := range row {
 			if tile.text == letter {
 				letters.alphabet[i][j].style = letters.onStyle
+				letters.used[letter] = true
+				break
+			}
+		}
+	}
+}
+
+// Flip a letter back off, for a guess that's been taken back
+func (letters *Keyboard) FlipOff(letter string) {
+	for i, row := range letters.alphabet {
+		for j, tile := range row {
+			if tile.text == letter {
+				letters.alphabet[i][j].style = letters.offStyle
+				delete(letters.used, letter)
 				break
 			}
 		}
@@ -242,52 +399,20 @@ func (letters *Keyboard) FlipOn(letter string) {
 type GraphicView struct {
 	// The graphic to show. Changes when player is wrong
 	currentGraphic PrettyString
-	// Call this repeatedly to get the next graphic
-	graphicGenerator func() (string, error)
+	// Which of the graphics is being shown
+	frame int
 	// If true, flash the graphic
 	flash bool
 	// The style to apply when flashing
 	flashStyle lipgloss.Style
 }
 
-var baseGraphicStyle = lipgloss.NewStyle().
-	Bold(true).
-	Border(lipgloss.RoundedBorder()).
-	BorderBackground(backgroundColor).
-	Background(backgroundColor)
-
-var graphicStyle = lipgloss.NewStyle().
-	Inherit(baseGraphicStyle).
-	Padding(1, 3, 1, 3).
-	Foreground(primaryColor).
-	BorderForeground(primaryColor)
-
-var flashWrongStyle = lipgloss.NewStyle().
-	Inherit(baseGraphicStyle).
-	Padding(1, 3, 1, 3).
-	Foreground(failColor).
-	BorderForeground(failColor)
-
-var flashCorrectStyle = lipgloss.NewStyle().
-	Inherit(baseGraphicStyle).
-	Padding(1, 3, 1, 3).
-	Foreground(successColor).
-	BorderForeground(successColor)
-
 func NewGraphicView() GraphicView {
-	// Set up the generator can call it once to get first graphic.
-	graphicGen := Graphics()
-	currentGraphic, err := graphicGen()
-	if err != nil {
-		panic(err)
-	}
-
 	return GraphicView{
 		currentGraphic: PrettyString{
-			text:  currentGraphic,
+			text:  Graphic(0),
 			style: graphicStyle,
 		},
-		graphicGenerator: graphicGen,
 		// "nil" style
 		flashStyle: lipgloss.NewStyle(),
 	}
@@ -300,11 +425,91 @@ func (g *GraphicView) View() string {
 	return g.currentGraphic.View()
 }
 
+// Show a different frame of the hangman graphic
+func (g *GraphicView) SetFrame(frame int) {
+	g.frame = frame
+	g.currentGraphic.text = Graphic(frame)
+}
+
 func (g *GraphicView) ResetFlash() {
 	g.currentGraphic.style = graphicStyle
 	g.flash = false
 }
 
+// ******************************************************************
+//
+//		Menu stuff
+//	A list of choices the player moves through with the arrow keys
+//
+// ******************************************************************
+type MenuItem struct {
+	title       string
+	description string
+}
+
+type Menu struct {
+	// Shown above the choices
+	heading string
+	items   []MenuItem
+	// Which item is highlighted
+	cursor int
+}
+
+func NewMenu(heading string, items []MenuItem) Menu {
+	return Menu{
+		heading: heading,
+		items:   items,
+	}
+}
+
+// Move the highlight up, wrapping around to the bottom
+func (menu *Menu) Up() {
+	menu.cursor = (menu.cursor - 1 + len(menu.items)) % len(menu.items)
+}
+
+// Move the highlight down, wrapping around to the top
+func (menu *Menu) Down() {
+	menu.cursor = (menu.cursor + 1) % len(menu.items)
+}
+
+// The index of the highlighted item
+func (menu Menu) Selected() int {
+	return menu.cursor
+}
+
+// Move the highlight to the item with this title, if there is one
+func (menu *Menu) Select(title string) {
+	for i, item := range menu.items {
+		if item.title == title {
+			menu.cursor = i
+		}
+	}
+}
+
+func (menu Menu) View() string {
+	result := []string{menuHeadingStyle.Render(menu.heading)}
+	// Line the descriptions up
+	widest := 0
+	for _, item := range menu.items {
+		if w := lipgloss.Width(item.title); w > widest {
+			widest = w
+		}
+	}
+	for i, item := range menu.items {
+		title := item.title + strings.Repeat(" ", widest-lipgloss.Width(item.title))
+		line := menuItemStyle.Render("  " + title)
+		if i == menu.cursor {
+			line = menuSelectedStyle.Render("> " + title)
+		}
+		if item.description != "" {
+			line += "  " + menuDescriptionStyle.Render(item.description)
+		}
+		result = append(result, line)
+	}
+	result = append(result, "", footerStyle.Render("↑/↓ to move, Enter to choose, ESC to go back."))
+	return lipgloss.JoinVertical(lipgloss.Left, result...)
+}
+
 // ******************************************************************
 //
 //		Player input area
@@ -332,6 +537,40 @@ func newInput() textinput.Model {
 	return ti
 }
 
+// A wider text input for solving the whole word at once
+func newSolveInput() textinput.Model {
+	ti := newInput()
+	ti.Placeholder = "Solve the whole thing!"
+	ti.CharLimit = maxSetupWordLength
+	ti.Width = maxSetupWordLength
+	ti.Validate = validateSolveInput()
+	return ti
+}
+
+// Only allow what could be in a word or phrase
+func validateSolveInput() textinput.ValidateFunc {
+	return func(s string) error {
+		for _, r := range s {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && !strings.ContainsRune(phrasePunctuation, r) {
+				return errors.New("not valid input")
+			}
+		}
+		return nil
+	}
+}
+
+// Check a guess typed anywhere but the input box, the same way the
+// input box would: one letter, or a whole word to solve
+func validateGuess(s string) error {
+	if s == "" {
+		return errors.New("not valid input")
+	}
+	if utf8.RuneCountInString(s) == 1 {
+		return validateInput()(s)
+	}
+	return validateSolveInput()(s)
+}
+
 // Only allow letter inputs
 func validateInput() textinput.ValidateFunc {
 	return func(s string) error {
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	Daily puzzle stuff
+//
+// Everyone gets the same word on the same (UTC) day, worked out from
+// the date alone so there's no server to talk to. It can be played
+// once a day and ends with a spoiler-free summary to share.
+// ******************************************************************
+
+// Puzzle #1 was played on this day
+var dailyEpoch = time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+// Shuffles the daily words. Changing it changes every puzzle.
+const dailySeed = 20220801
+
+// Daily puzzles are picked from words most people know
+var DailyDifficulty = game.Easy
+
+// Which daily puzzle it is at time t
+func DailyNumber(t time.Time) int {
+	return int(t.UTC().Sub(dailyEpoch).Hours()/24) + 1
+}
+
+// The word for daily puzzle n. The dictionary is shuffled once with a
+// fixed seed and the puzzles walk through it, so words don't repeat
+// until every one has been played.
+func DailyWord(n int) string {
+	pool := wordPool(dictionary, DailyDifficulty)
+	order := rand.New(rand.NewSource(dailySeed)).Perm(len(pool))
+	i := (n - 1) % len(pool)
+	if i < 0 {
+		i += len(pool)
+	}
+	return pool[order[i]]
+}
+
+// What's remembered between days
+type DailyState struct {
+	// The last puzzle started
+	Number int `json:"number"`
+	// Its share summary. Empty if it was quit part way through.
+	Summary string `json:"summary"`
+}
+
+// Where the daily state is kept
+func DefaultDailyPath() string {
+	return filepath.Join(dataDir(), "daily.json")
+}
+
+// Read the daily state. A missing file means no puzzle has been played.
+func LoadDaily(path string) (DailyState, error) {
+	var s DailyState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Write the daily state, creating directories as needed
+func SaveDaily(path string, s DailyState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// A summary of a finished daily puzzle that doesn't give the word away,
+// like "Hangman #412 ✅ 3/8 misses 🟩🟥🟩🟩🟥".
+// Each guess is a square: green for a hit, red for a miss. Hints are bulbs.
+func ShareSummary(n int, g *game.Game) string {
+	result := "❌"
+	if g.Won() {
+		result = "✅"
+	}
+	word := g.Word()
+	guesses := g.Guesses()
+	var squares strings.Builder
+	for i, guess := range guesses {
+		hit := strings.Contains(word, guess)
+		if utf8.RuneCountInString(guess) > 1 {
+			// A right solve ends the game with a win
+			hit = i == len(guesses)-1 && g.Won()
+		}
+		if hit {
+			squares.WriteString("🟩")
+		} else {
+			squares.WriteString("🟥")
+		}
+	}
+	squares.WriteString(strings.Repeat("💡", g.State().Hints))
+	return fmt.Sprintf("Hangman #%d %s %d/%d misses %s", n, result, g.Misses(), g.MaxMisses(), squares.String())
+}
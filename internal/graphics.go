@@ -1,7 +1,5 @@
 package internal
 
-import "errors"
-
 /*
 	Provide some basic graphics to use
 */
@@ -72,21 +70,29 @@ var graphics = [8]string{`
 `,
 }
 
-// Closure alert!
-// Every time this is called, the next graphic from above is returned
-// Basically a Python generator...
-func Graphics() func() (string, error) {
-	i := 0
-	cap := len(graphics)
+// Return the graphic for the given frame number.
+// Frames past the last graphic stay on the last graphic.
+func Graphic(frame int) string {
+	if frame < 0 {
+		frame = 0
+	}
+	if frame >= len(graphics) {
+		frame = len(graphics) - 1
+	}
+	return graphics[frame]
+}
 
-	return func() (string, error) {
-		if i == cap {
-			// no more graphics. Throw error
-			return "", errors.New("")
-		} else {
-			nextGraphic := graphics[i]
-			i++
-			return nextGraphic, nil
-		}
+// Pick the frame to show after some misses, spreading the graphics over
+// however many misses are allowed. The last frame shows up with one
+// miss to spare, so fewer allowed misses means frames get skipped.
+func FrameFor(misses, maxMisses int) int {
+	last := len(graphics) - 1
+	if maxMisses <= 1 {
+		return last
+	}
+	frame := misses * last / (maxMisses - 1)
+	if frame > last {
+		frame = last
 	}
+	return frame
 }
@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/braheezy/hangman/game"
+)
+
+func TestFrameFor(t *testing.T) {
+	last := len(graphics) - 1
+	for _, d := range game.Difficulties {
+		t.Run(d.Name, func(t *testing.T) {
+			if got := FrameFor(0, d.MaxMisses); got != 0 {
+				t.Errorf("no misses shows frame %d, want 0", got)
+			}
+			// The whole dude is drawn with one miss to spare
+			if got := FrameFor(d.MaxMisses-1, d.MaxMisses); got != last {
+				t.Errorf("one life left shows frame %d, want %d", got, last)
+			}
+			if got := FrameFor(d.MaxMisses, d.MaxMisses); got != last {
+				t.Errorf("lost shows frame %d, want %d", got, last)
+			}
+			// Every miss before that moves him along
+			prev := 0
+			for misses := 1; misses < d.MaxMisses; misses++ {
+				got := FrameFor(misses, d.MaxMisses)
+				if got <= prev {
+					t.Errorf("miss %d shows frame %d, no further on than %d", misses, got, prev)
+				}
+				prev = got
+			}
+		})
+	}
+
+	if got := FrameFor(20, 8); got != last {
+		t.Errorf("past the max shows frame %d, want %d", got, last)
+	}
+}
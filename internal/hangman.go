@@ -1,14 +1,19 @@
 package internal
 
 import (
+	"bytes"
 	"embed"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
+	"github.com/braheezy/hangman/game"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -29,39 +34,83 @@ var DictionaryFile, _ = f.ReadFile("dictionary.txt")
 
 func LoadWords() (words []string, err error) {
 	// Load dictionary into a list and return list
-	words = strings.Fields(string(DictionaryFile))
-	for i, word := range words {
-		words[i] = strings.ToUpper(word)
-	}
-
-	return words, nil
+	return ParseWords(bytes.NewReader(DictionaryFile), "dictionary.txt")
 }
 
 var dictionary, _ = LoadWords()
 
+// The words from a list that fit a difficulty.
+// Falls back to the whole list rather than leaving nothing to play.
+func wordPool(words []string, d game.Difficulty) []string {
+	pool := d.Filter(words)
+	if len(pool) == 0 {
+		return words
+	}
+	return pool
+}
+
 // ******************************************************************
 //
 //	Model stuff
 //
 // ******************************************************************
+// Which screen the player is looking at
+type screen int
+
+const (
+	// Guessing letters, or looking at the result of the last game
+	gameScreen screen = iota
+	// Picking a difficulty
+	difficultyScreen
+	// Looking at stats from every game played
+	statsScreen
+	// Player one entering a word for player two
+	setupScreen
+	// Picking a word pack
+	categoryScreen
+	// Signing a new high score
+	initialsScreen
+	// Looking at the leaderboard
+	highScoresScreen
+)
+
 type model struct {
+	// What the player is looking at
+	screen screen
 	// Struct for all things related to the hangman graphic
 	graphicView *GraphicView
-	// The word the player is trying to guess
-	word string
+	// The rules of the game: the word, the guesses, the lives
+	game *game.Game
 	// The "board" under the graphic where player guesses are shown
 	board Board
 	// Text area where player types their guesses
 	input textinput.Model
-	// All the letters the player has guessed
-	userGuesses []string
+	// Is the player trying to solve the whole word?
+	solving bool
+	// How many misses a wrong solve attempt costs
+	solvePenalty int
+	// Is the game dodging guesses?
+	evil bool
+	// The letter the last hint suggested
+	suggestion string
+	// The seed for the current game
+	seed int64
+	// The seed for the next game
+	nextSeed int64
 	// All the possible letters that can be guessed
 	keyboard     *Keyboard
 	showKeyboard bool
+	// The mouse button is down, and the key it went down on (if any).
+	// Letting go over the same key guesses it.
+	mouseDown bool
+	pressed   string
 	// The notice area thing
 	notice PrettyString
 	// Did game end?
 	gameOver bool
+	// How the session has gone so far
+	wins   int
+	losses int
 	// Title banner
 	title     PrettyString
 	showTitle bool
@@ -70,57 +119,289 @@ type model struct {
 	// Dimensions of terminal windows
 	height int
 	width  int
-	// If the board is cut off, this is how many tiles are being cut
-	numCutoffTiles int
 	// Any errors caught go here and should be reported somewhere
 	err error
+	// Every word that could be played
+	wordList []string
+	// The words to go back to when no category is picked
+	baseWords []string
+	// The word pack being played, if any
+	category string
+	// Every word pack, for the category picker
+	packs        []WordPack
+	categoryMenu Menu
+	// How hard the game is and the words that fit it
+	difficulty game.Difficulty
+	words      []string
+	// The difficulty picker
+	menu Menu
+	// When the current game started
+	started time.Time
+	// Where finished games are recorded. Empty means they aren't.
+	statsPath string
+	// What the stats screen shows
+	summary Summary
+	// Is player one picking the words?
+	twoPlayer bool
+	// Only let player one pick words from the word list
+	checkWords bool
+	// Where player one enters the word
+	setup wordSetup
+	// Shown above the board to help the player out
+	hint string
+	// The daily puzzle being played. Zero means it's not a daily game.
+	daily int
+	// Where the daily puzzle is marked as played
+	dailyPath string
+	// The spoiler-free summary of a finished daily puzzle
+	share string
+	// The clock, if the game is timed
+	countdown Countdown
+	// How many words have been solved in this race
+	raceSolved int
+	// Points from the finished games in this run, and wins in a row
+	score  int
+	streak int
+	// Is the game keeping score? Not when the word is known ahead of time.
+	scoring bool
+	// The run is over. Its score goes when the next game starts.
+	runOver bool
+	// Where the leaderboard is kept. Empty means it isn't.
+	scoresPath string
+	// The run made the leaderboard and is waiting to be signed
+	highScore bool
+	initials  textinput.Model
+	// Quit once the high score is signed (or skipped)
+	quitting bool
+	// What the leaderboard screen shows, and which row is new (or -1)
+	highScores HighScores
+	newRank    int
+	// Is the game being played on some other terminal, like over SSH?
+	remote bool
+	// Where replays are saved. Empty means they aren't.
+	replayDir string
+	// Everything the player did this game, for the replay
+	moves []Move
+	// Where this game's replay went, once it's finished
+	replayPath string
+	// Can guesses be taken back? Practice games aren't recorded.
+	practice bool
+	// The game as it was before each guess, for undo
+	history []undoPoint
+}
+
+// Ways to customize the game from the command line
+type Options struct {
+	Difficulty game.Difficulty
+	// Words to play instead of the dictionary
+	Words []string
+	// The name of the word pack Words came from, shown to the player as a hint
+	Category string
+	// Word packs the player can pick from
+	Packs []WordPack
+	// Start with the category picker
+	StartMenu bool
+	// Colors to use. The zero Theme keeps the default colors.
+	Theme Theme
+	// Where to record finished games. Empty means don't.
+	StatsPath string
+	// Player one enters each word for player two
+	TwoPlayer bool
+	// In two player mode, only accept words from the word list
+	CheckWords bool
+	// How many misses a wrong solve attempt costs. Zero means the default.
+	SolvePenalty int
+	// Evil mode: the game dodges guesses instead of picking a word up front
+	Evil bool
+	// Seeds the first game. The same seed and options play the same word.
+	Seed int64
+	// Play this daily puzzle instead of random words. Zero means don't.
+	Daily int
+	// Where finished daily puzzles are remembered
+	DailyPath string
+	// Put each guess, or the whole session, on the clock
+	Timer TimerMode
+	// How long the clock runs. Zero means the timer's default.
+	TimeLimit time.Duration
+	// Where to keep the high scores. Empty means don't.
+	ScoresPath string
+	// The game is played on some other terminal, like over SSH, so
+	// leave this one alone
+	Remote bool
+	// Save a replay of every game here. Empty means don't.
+	ReplayDir string
+	// Let guesses be taken back, and don't keep stats or scores
+	Practice bool
 }
 
-func initialModel() model {
-	// Get random word from dictionary
-	word := dictionary[rand.Intn(len(dictionary))]
+func initialModel(opts Options) model {
+	wordList := dictionary
+	if len(opts.Words) > 0 {
+		wordList = opts.Words
+	}
+	baseWords := wordList
+	baseDescription := "Every word in your word list"
+	if opts.Category != "" || len(opts.Words) == 0 {
+		baseWords = dictionary
+		baseDescription = "Every word in the dictionary"
+	}
 
-	// Make a new board based on word length
-	board := NewBoard(len(word), boardTileStyle)
+	title := NewTitle()
 
-	// New input area
-	textInput := newInput()
+	footer := NewFooter()
+
+	m := model{
+		showKeyboard: true,
+		title:        title,
+		showTitle:    true,
+		footer:       footer,
+		difficulty:   opts.Difficulty,
+		wordList:     wordList,
+		baseWords:    baseWords,
+		category:     opts.Category,
+		packs:        opts.Packs,
+		words:        wordPool(wordList, opts.Difficulty),
+		menu:         newDifficultyMenu(),
+		statsPath:    opts.StatsPath,
+		twoPlayer:    opts.TwoPlayer,
+		checkWords:   opts.CheckWords,
+		solvePenalty: opts.SolvePenalty,
+		evil:         opts.Evil,
+		nextSeed:     opts.Seed,
+		daily:        opts.Daily,
+		dailyPath:    opts.DailyPath,
+		countdown:    NewCountdown(opts.Timer, opts.TimeLimit),
+		scoring:      !opts.TwoPlayer && opts.Daily == 0 && !opts.Practice,
+		scoresPath:   opts.ScoresPath,
+		remote:       opts.Remote,
+		replayDir:    opts.ReplayDir,
+		practice:     opts.Practice,
+	}
+	m.categoryMenu = newCategoryMenu(m.packs, baseDescription)
+	resetGame(&m)
+	if opts.StartMenu && len(m.packs) > 0 {
+		m.screen = categoryScreen
+		// The game behind the menu is only played if it's skipped.
+		// Whatever's picked plays the first seed instead.
+		m.nextSeed = m.seed
+	}
+	return m
+}
+
+// Roll a new word and reset everything tied to the previous one.
+// The session tally and window dimensions survive.
+func resetGame(m *model) {
+	if m.twoPlayer {
+		// Player one picks the next word
+		m.setup = newWordSetup()
+		m.screen = setupScreen
+		return
+	}
+	if m.daily > 0 {
+		// Everyone plays the same word today. It counts as played from
+		// now, so quitting part way doesn't get you another go.
+		startGame(m, DailyWord(m.daily), fmt.Sprintf("Daily puzzle #%d", m.daily))
+		if err := SaveDaily(m.dailyPath, DailyState{Number: m.daily}); err != nil {
+			m.err = fmt.Errorf("couldn't save the daily puzzle: %w", err)
+		}
+		return
+	}
+	// Get random word from the words that fit the difficulty
+	m.seed = m.nextSeed
+	var word string
+	word, m.nextSeed = pickWord(m.words, m.seed)
+	hint := ""
+	if m.category != "" {
+		hint = "Category: " + m.category
+	}
+	startGame(m, word, hint)
+}
+
+// Pick a word with a game's seed. Each game gets its own seed so any
+// one of them can be replayed. The next game's seed comes from this
+// game's randomness, and is returned too.
+func pickWord(words []string, seed int64) (string, int64) {
+	rng := rand.New(rand.NewSource(seed))
+	word := words[rng.Intn(len(words))]
+	return word, rng.Int63()
+}
+
+// The engine keeps track of guesses and lives. An evil one dodges
+// guesses using words. A solve penalty of zero keeps the default.
+func newEngine(word string, words []string, d game.Difficulty, evil bool, solvePenalty int) *game.Game {
+	var g *game.Game
+	if evil {
+		g = game.NewEvil(word, words, d.MaxMisses)
+	} else {
+		g = game.New(word, d.MaxMisses)
+	}
+	if solvePenalty > 0 {
+		g.SetSolvePenalty(solvePenalty)
+	}
+	return g
+}
+
+// Start a game for word. The hint (if any) is shown above the board.
+func startGame(m *model, word string, hint string) {
+	m.screen = gameScreen
+	m.hint = hint
+	m.suggestion = ""
+
+	// Player one's word is final, so only random games can be evil
+	m.game = newEngine(word, m.words, m.difficulty, m.evil && !m.twoPlayer, m.solvePenalty)
 
-	// Empty list to hold userGuesses
-	var userGuesses []string
+	// Make a new board based on the word, with gaps for any spaces
+	m.board = NewPuzzleBoard(m.game.Word())
+
+	// New input area
+	m.input = newInput()
+	m.solving = false
 
 	// Graphic stuff
 	graphicView := NewGraphicView()
-
-	notice := NewNotice()
+	m.graphicView = &graphicView
 
 	keyboard := NewKeyboard()
+	m.keyboard = &keyboard
 
-	title := NewTitle()
+	m.notice = NewNotice()
+	m.footer.text = guessingFooter(m)
+	m.gameOver = false
+	m.err = nil
+	m.started = time.Now()
+	m.moves = nil
+	m.replayPath = ""
+	m.history = nil
 
-	footer := NewFooter()
+	// A new run starts from nothing
+	if m.runOver {
+		m.score = 0
+		m.streak = 0
+		m.runOver = false
+		m.highScore = false
+	}
+
+	switch m.countdown.mode {
+	case GuessTimer:
+		m.countdown.Reset(m.started)
+	case RaceTimer:
+		// The race clock keeps running from word to word
+		if m.countdown.Expired() {
+			m.countdown.Reset(m.started)
+			m.raceSolved = 0
+		}
+	}
 
-	return model{
-		graphicView:    &graphicView,
-		word:           word,
-		board:          board,
-		input:          textInput,
-		userGuesses:    userGuesses,
-		keyboard:       &keyboard,
-		showKeyboard:   true,
-		notice:         notice,
-		gameOver:       false,
-		title:          title,
-		showTitle:      true,
-		footer:         footer,
-		height:         0,
-		width:          0,
-		numCutoffTiles: 0,
-		err:            nil,
+	// The new word may need more (or less) room than the last one
+	if m.width > 0 {
+		handleScreenResize(m)
 	}
 }
 
 func (m model) Init() tea.Cmd {
+	if m.countdown.On() {
+		return tea.Batch(textinput.Blink, tick())
+	}
 	return textinput.Blink
 }
 
@@ -129,17 +410,6 @@ func (m model) Init() tea.Cmd {
 //	Update stuff
 //
 // ******************************************************************
-// Return list of indexes where letters occur in string
-func Indexes(s string, letter string) []int {
-	var indexes []int
-	for i, c := range s {
-		if string(c) == letter {
-			indexes = append(indexes, i)
-		}
-	}
-	return indexes
-}
-
 // Update model based on user guess
 func handleGuess(m *model) {
 	// Did the player enter anything before pressing return?
@@ -150,61 +420,556 @@ func handleGuess(m *model) {
 	// Putting it here means it only clears when the user guesses again.
 	m.notice.text = ""
 
-	// Pull out the letter
-	guess := strings.ToUpper(m.input.Value())
-
-	// Can't guess letters already guessed
-	if slices.Contains(m.userGuesses, guess) {
+	// Let the engine decide what the guess means
+	before := saveUndoPoint(m)
+	var result game.Result
+	var err error
+	if m.solving {
+		result, err = m.game.Solve(m.input.Value())
+	} else {
+		result, err = m.game.Guess(m.input.Value())
+	}
+	if err == nil {
+		kind := MoveGuess
+		if result.Solve {
+			kind = MoveSolve
+		}
+		recordMove(m, kind, result.Letter)
+		pushUndoPoint(m, before)
+	}
+	switch {
+	case errors.Is(err, game.ErrAlreadyGuessed):
+		// Can't guess letters already guessed
 		m.notice.text = "Silly, you already guessed that! Try again"
+	case errors.Is(err, game.ErrInvalidSolve):
+		m.notice.text = "That's not much of a guess. Try some letters"
+	case err != nil:
+		m.err = err
+	case result.Hit():
+		// The guess is a hit! Start "flipping" tiles
+		pattern := []rune(m.game.Pattern())
+		for _, id := range result.Positions {
+			m.board[id].text = string(pattern[id])
+		}
+		// Update model to flash for correct guess on next render
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashCorrectStyle
+		if !result.Solve {
+			m.keyboard.FlipOn(result.Letter)
+		}
+	default:
+		// Wrong guess! increment graphics
+		m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+		// Update model to flash for incorrect guess on next render
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashWrongStyle
+		if result.Solve {
+			m.notice.text = fmt.Sprintf("Nope, it's not %s!", result.Letter)
+		} else {
+			m.keyboard.FlipOn(result.Letter)
+		}
+	}
+	// Clear the input area, and go back to letters after a solve attempt
+	if m.solving {
+		stopSolving(m)
+	}
+	m.input.Reset()
+
+	// A fresh clock for the next guess
+	if err == nil && m.countdown.mode == GuessTimer {
+		m.countdown.Reset(time.Now())
+	}
+	endGame(m)
+}
+
+// Check if the game is over and wrap it up if it is
+func endGame(m *model) {
+	switch m.game.Status() {
+	case game.Won:
+		m.wins++
+		m.notice.text = "Woo you win!"
+		m.notice.style = winNoticeStyle
+		m.gameOver = true
+	case game.Lost:
+		m.losses++
+		m.notice.text = fmt.Sprintf("You lose :(\nThe hidden word was: %s", m.game.Word())
+		m.notice.style = loseNoticeStyle
+		m.gameOver = true
+	}
+	if m.gameOver {
+		bankScore(m)
+	}
+	if m.gameOver && m.countdown.mode == RaceTimer && !m.countdown.Expired() {
+		nextRaceWord(m)
+		return
+	}
+	if m.gameOver {
+		// Nothing left to type, offer the post-game choices instead
+		m.input.Blur()
+		m.footer.text = gameOverFooter(m)
+		recordGame(m)
+		saveReplay(m)
+		if m.daily > 0 {
+			finishDaily(m)
+		}
+		// Losing a word ends the run
+		if m.game.Lost() {
+			endRun(m)
+		}
+	}
+}
+
+// The points for the game being played
+func gameScore(m *model) game.Score {
+	return game.ScoreGame(m.game.State(), m.difficulty, m.streak)
+}
+
+// Add a finished game's points to the run
+func bankScore(m *model) {
+	if !m.scoring {
+		return
+	}
+	m.score += gameScore(m).Total
+	if m.game.Won() {
+		m.streak++
 	} else {
-		// See if the guess is one of the letters in the word
-		ids := Indexes(m.word, guess)
-		if len(ids) > 0 {
-			// The guess is a hit! Start "flipping" tiles
-			for _, id := range ids {
-				m.board[id].text = guess
+		m.streak = 0
+	}
+}
+
+// The run is over. See if it made the leaderboard.
+func endRun(m *model) {
+	if !m.scoring {
+		return
+	}
+	m.runOver = true
+	if m.scoresPath == "" {
+		return
+	}
+	h, err := LoadHighScores(m.scoresPath)
+	if err != nil {
+		m.err = err
+		return
+	}
+	if h.Qualifies(m.score) {
+		m.highScore = true
+		m.footer.text = highScoreFooterText
+	}
+}
+
+// Quit, unless the run made the leaderboard. Then it's signed first.
+func quit(m *model) tea.Cmd {
+	if !m.runOver {
+		endRun(m)
+	}
+	if !m.highScore {
+		return tea.Quit
+	}
+	m.quitting = true
+	m.initials = newInitialsInput()
+	m.screen = initialsScreen
+	return textinput.Blink
+}
+
+// The run's points so far, counting the game being played
+func liveScore(m *model) int {
+	if m.gameOver || m.runOver {
+		return m.score
+	}
+	return m.score + gameScore(m).Total
+}
+
+// Load the leaderboard and switch to it
+func showHighScores(m *model, newRank int) {
+	h, err := LoadHighScores(m.scoresPath)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.highScores = h
+	m.newRank = newRank
+	m.screen = highScoresScreen
+}
+
+// Type initials for a new high score. Enter saves it, ESC skips it.
+func handleInitials(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.quitting {
+				return m, tea.Quit
 			}
-			// Update model to flash for correct guess on next render
-			m.graphicView.flash = true
-			m.graphicView.flashStyle = flashCorrectStyle
-		} else {
-			// Wrong guess! increment graphics
-			graphic, err := m.graphicView.graphicGenerator()
-			// Update model to flash for incorrect guess on next render
-			m.graphicView.flash = true
-			m.graphicView.flashStyle = flashWrongStyle
+			m.highScore = false
+			m.footer.text = gameOverFooterText
+			m.screen = gameScreen
+			return m, nil
+		case "enter":
+			h, rank, err := AddHighScore(m.scoresPath, HighScore{
+				Initials:   initialsOf(m.initials),
+				Score:      m.score,
+				Difficulty: gameKind(&m),
+				Date:       time.Now(),
+			})
+			m.highScore = false
+			m.footer.text = gameOverFooterText
 			if err != nil {
-				// No more graphics to get. Player loses!
-				m.notice.text = fmt.Sprintf("You lose :(\nThe hidden word was: %s", m.word)
-				m.notice.style = loseNoticeStyle
-				m.gameOver = true
-			} else {
-				m.graphicView.currentGraphic.text = graphic
+				m.err = fmt.Errorf("couldn't save the high score: %w", err)
+				m.quitting = false
+				m.screen = gameScreen
+				return m, nil
 			}
+			m.highScores = h
+			m.newRank = rank
+			m.screen = highScoresScreen
+			return m, nil
 		}
-		// Remember userGuesses for next loop
-		m.userGuesses = append(m.userGuesses, guess)
-		m.keyboard.FlipOn(guess)
 	}
-	// Clear the input area
-	m.input.Reset()
+	var cmd tea.Cmd
+	m.initials, cmd = m.initials.Update(msg)
+	return m, cmd
+}
 
-	// If there aren't any more blank tiles then word is filled! Winner!
-	if !m.board.Contains(blankBoardTile) {
-		m.notice.text = "Woo you win! Feel free to re-run the program to play again!"
+// In a race, a finished word goes straight on to the next one
+func nextRaceWord(m *model) {
+	recordGame(m)
+	saveReplay(m)
+	word := m.game.Word()
+	won := m.game.Won()
+	if won {
+		m.raceSolved++
+	}
+	resetGame(m)
+	if won {
+		m.notice.text = fmt.Sprintf("Got it! It was %s. Next!", word)
 		m.notice.style = winNoticeStyle
-		m.gameOver = true
+	} else {
+		m.notice.text = fmt.Sprintf("Missed that one, it was %s. Next!", word)
+		m.notice.style = loseNoticeStyle
+	}
+}
+
+// Keep the clock up to date and act on it running out.
+// Ticks keep coming for as long as there's a timer.
+func handleTick(m model, t time.Time) (tea.Model, tea.Cmd) {
+	// Only count while there's a word being guessed
+	counting := m.screen == gameScreen && !m.gameOver
+	m.countdown.Update(t, counting)
+	if counting && m.countdown.Expired() {
+		handleTimeout(&m, t)
+	}
+	return m, tick()
+}
+
+// The clock ran out
+func handleTimeout(m *model, now time.Time) {
+	if m.countdown.mode == RaceTimer {
+		endRace(m)
+		return
+	}
+	// Too slow costs a life, then the next guess gets a fresh clock
+	if err := m.game.TimeOut(); err != nil {
+		m.err = err
+		return
+	}
+	recordMove(m, MoveTimeout, "")
+	m.countdown.Reset(now)
+	m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+	m.graphicView.flash = true
+	m.graphicView.flashStyle = flashWrongStyle
+	m.notice.text = "Too slow! That cost a life."
+	m.notice.style = noticeStyle
+	endGame(m)
+}
+
+// Time's up for the whole race. The word in progress doesn't count.
+func endRace(m *model) {
+	m.gameOver = true
+	words := "words"
+	if m.raceSolved == 1 {
+		words = "word"
+	}
+	m.notice.text = fmt.Sprintf("Time's up! You solved %d %s.\nThe last word was: %s", m.raceSolved, words, m.game.Word())
+	m.notice.style = winNoticeStyle
+	if m.raceSolved == 0 {
+		m.notice.style = loseNoticeStyle
+	}
+	if m.solving {
+		stopSolving(m)
+	}
+	m.input.Blur()
+	m.footer.text = gameOverFooterText
+	endRun(m)
+}
+
+// Make the summary to share and save it with today's puzzle
+func finishDaily(m *model) {
+	m.share = ShareSummary(m.daily, m.game)
+	m.footer.text = dailyFooterText
+	err := SaveDaily(m.dailyPath, DailyState{Number: m.daily, Summary: m.share})
+	if err != nil {
+		m.err = fmt.Errorf("couldn't save the daily puzzle: %w", err)
+	}
+}
+
+// Put the daily summary on the clipboard
+func copyShare(m *model) {
+	if err := clipboard.WriteAll(m.share); err != nil {
+		m.notice.text = fmt.Sprintf("Couldn't copy: %v", err)
+		return
+	}
+	m.notice.text = "Copied! Paste it anywhere to share."
+}
+
+// Ask the solver for the best next letter. It costs a miss, unless the
+// last hint hasn't been used yet.
+func handleHint(m *model) {
+	if m.suggestion != "" && !slices.Contains(m.game.Guesses(), m.suggestion) {
+		m.notice.text = fmt.Sprintf("Hint: try %s", m.suggestion)
+		return
+	}
+	before := saveUndoPoint(m)
+	hint, err := m.game.Hint(m.words)
+	switch {
+	case errors.Is(err, game.ErrNoHintsLeft):
+		m.notice.text = "No hints with one life left. You're on your own!"
+	case err != nil:
+		m.err = err
+	default:
+		pushUndoPoint(m, before)
+		m.suggestion = hint.Letter
+		recordMove(m, MoveHint, hint.Letter)
+		m.notice.text = fmt.Sprintf("Hint: try %s", hint.Letter)
+		m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashWrongStyle
 	}
 }
 
+// Letters on the keyboard light up under the mouse, and clicking one
+// guesses it. A click is a press and a release on the same key, since
+// dragging with the button down looks just like pressing.
+func handleMouse(m model, msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	playing := m.screen == gameScreen && !m.gameOver && !m.solving && m.showKeyboard
+	letter, ok := "", false
+	if playing {
+		// The clock can change width between resizes, moving the keyboard
+		layoutKeyboard(&m)
+		letter, ok = m.keyboard.At(msg.X, msg.Y)
+		if ok && m.keyboard.Used(letter) {
+			// Nothing to do with a letter that's been guessed
+			letter, ok = "", false
+		}
+	}
+
+	clicked := false
+	switch msg.Type {
+	case tea.MouseLeft:
+		// Only where the button went down counts, not where it's dragged
+		if !m.mouseDown {
+			m.mouseDown = true
+			m.pressed = letter
+		}
+	case tea.MouseRelease:
+		clicked = ok && m.mouseDown && letter == m.pressed
+		m.mouseDown = false
+		m.pressed = ""
+	}
+
+	if !playing {
+		m.keyboard.Hover("")
+		return m, nil
+	}
+	m.keyboard.Hover(letter)
+	if clicked {
+		m.input.SetValue(letter)
+		handleGuess(&m)
+		m.keyboard.Hover("")
+	}
+	return m, nil
+}
+
+// Swap the letter input for the whole word input
+func startSolving(m *model) {
+	m.solving = true
+	m.input = newSolveInput()
+	m.footer.text = solveFooterText
+}
+
+// Back to guessing letters
+func stopSolving(m *model) {
+	m.solving = false
+	m.input = newInput()
+	m.footer.text = guessingFooter(m)
+}
+
+// What the footer says while guessing
+func guessingFooter(m *model) string {
+	if m.practice {
+		return practiceFooterText
+	}
+	return footerText
+}
+
+// What the footer says once the game is over
+func gameOverFooter(m *model) string {
+	if m.practice {
+		return practiceGameOverFooterText
+	}
+	return gameOverFooterText
+}
+
+// ******************************************************************
+//
+//	Undo stuff
+//
+// In practice mode a guess (or hint) can be taken back. The engine
+// only goes forward, so a copy of it is kept from before each one.
+// ******************************************************************
+type undoPoint struct {
+	game *game.Game
+	// How many moves the replay had
+	moves      int
+	suggestion string
+}
+
+// The game as it is now, if it can be gone back to
+func saveUndoPoint(m *model) undoPoint {
+	if !m.practice {
+		return undoPoint{}
+	}
+	return undoPoint{game: m.game.Clone(), moves: len(m.moves), suggestion: m.suggestion}
+}
+
+// Remember where to go back to once a guess has counted
+func pushUndoPoint(m *model, p undoPoint) {
+	if p.game != nil {
+		m.history = append(m.history, p)
+	}
+}
+
+// Take back the last guess or hint
+func handleUndo(m *model) {
+	if len(m.history) == 0 {
+		m.notice.text = "Nothing to undo!"
+		m.notice.style = noticeStyle
+		return
+	}
+	last := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+
+	// The game's over until it isn't
+	switch m.game.Status() {
+	case game.Won:
+		m.wins--
+	case game.Lost:
+		m.losses--
+	}
+	taken := m.game.Guesses()[len(last.game.Guesses()):]
+	hints := m.game.Hints() - last.game.Hints()
+	m.game = last.game
+	m.suggestion = last.suggestion
+	if last.moves <= len(m.moves) {
+		m.moves = m.moves[:last.moves]
+	}
+
+	// Put everything back the way it looked
+	for _, guess := range taken {
+		m.keyboard.FlipOff(guess)
+	}
+	pattern := []rune(m.game.Pattern())
+	for i, r := range pattern {
+		if r == game.Blank {
+			m.board[i].text = blankBoardTile
+		}
+	}
+	m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+
+	m.notice.style = noticeStyle
+	switch {
+	case len(taken) > 0:
+		m.notice.text = fmt.Sprintf("Took back %s", strings.Join(taken, ", "))
+	case hints > 0:
+		m.notice.text = "Took back the hint"
+	}
+	if m.gameOver {
+		m.gameOver = false
+		m.footer.text = guessingFooter(m)
+		m.input.Focus()
+	}
+}
+
+// Save the finished game to the stats file. Practice doesn't count.
+func recordGame(m *model) {
+	if m.statsPath == "" || m.practice {
+		return
+	}
+	err := RecordGame(m.statsPath, GameRecord{
+		Word:       m.game.Word(),
+		Guesses:    m.game.Guesses(),
+		Misses:     m.game.Misses(),
+		MaxMisses:  m.game.MaxMisses(),
+		Won:        m.game.Won(),
+		Difficulty: gameKind(m),
+		Category:   m.category,
+		Seed:       m.gameSeed(),
+		Started:    m.started,
+		DurationMS: time.Since(m.started).Milliseconds(),
+	})
+	if err != nil {
+		m.err = fmt.Errorf("couldn't save stats: %w", err)
+	}
+}
+
+// The seed that replays this game. Player one's words and the daily
+// puzzle have no seed.
+func (m model) gameSeed() int64 {
+	if m.twoPlayer || m.daily > 0 {
+		return 0
+	}
+	return m.seed
+}
+
+// How the game is labelled in the stats
+func gameKind(m *model) string {
+	if m.twoPlayer {
+		return "Two-player"
+	}
+	if m.daily > 0 {
+		return "Daily"
+	}
+	if m.evil {
+		return m.difficulty.Name + " (evil)"
+	}
+	if m.practice {
+		return m.difficulty.Name + " (practice)"
+	}
+	return m.difficulty.Name
+}
+
+// Load the stats file and switch to the stats screen
+func showStats(m *model) {
+	stats, err := LoadStats(m.statsPath)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.summary = stats.Summarize()
+	m.screen = statsScreen
+}
+
 // Update model based on terminal resizing.
 // Clear the screen if required.
 func handleScreenResize(m *model) {
 	// Hide keyboard if there isn't enough room
 	maxWidth := lipgloss.Width(m.graphicView.View()) + lipgloss.Width(m.keyboard.View())
+	if m.countdown.On() {
+		maxWidth += lipgloss.Width(m.countdown.View())
+	}
 	if m.width < maxWidth {
 		m.showKeyboard = false
-		ClearScreen()
+		m.clearScreen()
 	} else {
 		m.showKeyboard = true
 	}
@@ -213,26 +978,111 @@ func handleScreenResize(m *model) {
 	maxWidth = lipgloss.Width(m.title.View())
 	if m.width < maxWidth {
 		m.showTitle = false
-		ClearScreen()
+		m.clearScreen()
 	} else {
 		m.showTitle = true
 	}
 
-	// Count how many tiles are cut off if there isn't enough room
+	// The board wraps if there isn't enough room. See View()
 	maxWidth = lipgloss.Width(m.board.View(" "))
 	if m.width < maxWidth {
-		tileSize := lipgloss.Width(m.board[0].View())
-		m.numCutoffTiles = (maxWidth-m.width)/tileSize + 1
-		ClearScreen()
-	} else {
-		m.numCutoffTiles = 0
+		m.clearScreen()
 	}
 
+	layoutKeyboard(m)
+}
+
+// The difficulty picker lists every preset
+func newDifficultyMenu() Menu {
+	var items []MenuItem
+	for _, d := range game.Difficulties {
+		items = append(items, MenuItem{
+			title:       d.Name,
+			description: fmt.Sprintf("%s (%d lives)", d.Description, d.MaxMisses),
+		})
+	}
+	return NewMenu("Choose a difficulty", items)
+}
+
+// Move around the difficulty picker. Choosing one starts a new game.
+func handleDifficultyMenu(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.screen = gameScreen
+	case "up", "k":
+		m.menu.Up()
+	case "down", "j":
+		m.menu.Down()
+	case "enter":
+		m.difficulty = game.Difficulties[m.menu.Selected()]
+		m.words = wordPool(m.wordList, m.difficulty)
+		m.screen = gameScreen
+		resetGame(&m)
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+// The name of the "no category" choice in the category picker
+const noCategory = "No category"
+
+// The category picker lists every word pack after a choice for no pack.
+// The description says what no pack means.
+func newCategoryMenu(packs []WordPack, description string) Menu {
+	items := []MenuItem{{title: noCategory, description: description}}
+	for _, p := range packs {
+		items = append(items, MenuItem{
+			title:       p.Name,
+			description: fmt.Sprintf("%s (%d words)", p.Description, len(p.Words)),
+		})
+	}
+	return NewMenu("Choose a category", items)
+}
+
+// Move around the category picker. Choosing one starts a new game.
+func handleCategoryMenu(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.screen = gameScreen
+	case "up", "k":
+		m.categoryMenu.Up()
+	case "down", "j":
+		m.categoryMenu.Down()
+	case "enter":
+		if i := m.categoryMenu.Selected(); i == 0 {
+			m.category = ""
+			m.wordList = m.baseWords
+		} else {
+			m.category = m.packs[i-1].Name
+			m.wordList = m.packs[i-1].Words
+		}
+		m.words = wordPool(m.wordList, m.difficulty)
+		resetGame(&m)
+		return m, textinput.Blink
+	}
+	return m, nil
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// The clock ticks on every screen
+	if t, ok := msg.(tickMsg); ok {
+		return handleTick(m, time.Time(t))
+	}
+
+	// There's no game yet while player one picks the word
+	if m.screen == setupScreen {
+		return handleSetup(m, msg)
+	}
+	if m.screen == initialsScreen {
+		return handleInitials(m, msg)
+	}
+
 	// Clear out any flash status. This line is what makes it flash!
 	if m.graphicView.flash {
 		m.graphicView.ResetFlash()
@@ -240,18 +1090,106 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		switch m.screen {
+		case difficultyScreen:
+			return handleDifficultyMenu(m, msg)
+		case categoryScreen:
+			return handleCategoryMenu(m, msg)
+		case statsScreen, highScoresScreen:
+			// Any key goes back, or finishes quitting
+			if msg.String() == "ctrl+c" || m.quitting {
+				return m, tea.Quit
+			}
+			m.screen = gameScreen
+			return m, nil
+		}
+		if m.gameOver && m.daily > 0 {
+			// One puzzle a day, so there's nothing to play again
+			switch msg.String() {
+			case "esc", "ctrl+c", "q", "enter":
+				return m, tea.Quit
+			case "y":
+				copyShare(&m)
+			case "s":
+				if m.statsPath != "" {
+					showStats(&m)
+				}
+			}
+			return m, nil
+		}
+		if m.gameOver {
+			// Post-game screen: play again, change difficulty, or quit
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "q":
+				return m, quit(&m)
+			case "u", "ctrl+z":
+				if m.practice {
+					handleUndo(&m)
+					return m, textinput.Blink
+				}
+			case "enter", "r":
+				if m.highScore {
+					// Sign the high score before it's gone
+					m.initials = newInitialsInput()
+					m.screen = initialsScreen
+					return m, textinput.Blink
+				}
+				resetGame(&m)
+				return m, textinput.Blink
+			case "d":
+				m.menu.Select(m.difficulty.Name)
+				m.screen = difficultyScreen
+			case "s":
+				if m.statsPath != "" {
+					showStats(&m)
+				}
+			case "h":
+				if m.scoresPath != "" {
+					showHighScores(&m, -1)
+				}
+			case "c":
+				if len(m.packs) > 0 && !m.twoPlayer {
+					m.categoryMenu.Select(m.category)
+					m.screen = categoryScreen
+				}
+			}
+			return m, nil
+		}
 		switch msg.String() {
-		case "esc", "ctrl+c":
+		case "ctrl+c":
 			return m, tea.Quit
+		case "esc":
+			if m.solving {
+				stopSolving(&m)
+				return m, textinput.Blink
+			}
+			return m, quit(&m)
+		case "/":
+			if !m.solving {
+				startSolving(&m)
+				return m, textinput.Blink
+			}
+		case "?":
+			if !m.solving {
+				handleHint(&m)
+				return m, nil
+			}
+		case "ctrl+z":
+			// A plain u is for guessing U while there's a word to guess
+			if m.practice {
+				handleUndo(&m)
+				return m, nil
+			}
 		case "enter":
 			// The player has guessed something. Process it.
 			handleGuess(&m)
-			if m.gameOver {
-				// TODO: Could allow "play again" feature
-				return m, tea.Quit
-			}
 		}
 
+	case tea.MouseMsg:
+		return handleMouse(m, msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Width
@@ -271,21 +1209,68 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 //	View stuff
 //
 // ******************************************************************
-func (m model) View() string {
-	// Build up pieces for top half of view
-	// Get the title
-	title := ""
+
+// What goes at the top of the game screen: the title, and under it the
+// graphic, clock and keyboard side by side. Each is empty if hidden.
+func topPieces(m *model) (title, graphic, timer, keyboard string) {
 	if m.showTitle {
 		title = m.title.View()
 	}
-
-	keyboardElement := ""
+	graphic = m.graphicView.View()
+	if m.countdown.On() {
+		timer = m.countdown.View()
+	}
 	if m.showKeyboard {
-		keyboardElement = m.keyboard.View()
+		keyboard = m.keyboard.View()
+	}
+	return title, graphic, timer, keyboard
+}
+
+// Work out where View puts the keyboard, so the mouse can find it
+func layoutKeyboard(m *model) {
+	if !m.showKeyboard {
+		return
+	}
+	title, graphic, timer, keyboard := topPieces(m)
+	midView := lipgloss.JoinHorizontal(lipgloss.Center, graphic, timer, keyboard)
+	width := lipgloss.Width(midView)
+	if w := lipgloss.Width(title); w > width {
+		width = w
+	}
+	m.keyboard.Place(
+		centered(width-lipgloss.Width(midView))+lipgloss.Width(graphic)+lipgloss.Width(timer),
+		lipgloss.Height(title)+centered(lipgloss.Height(midView)-lipgloss.Height(keyboard)),
+	)
+}
+
+func (m model) View() string {
+	switch m.screen {
+	case difficultyScreen:
+		return m.menu.View() + "\n"
+	case categoryScreen:
+		return m.categoryMenu.View() + "\n"
+	case statsScreen:
+		return m.summary.View() + "\n\n" + footerStyle.Render("Press any key to go back.") + "\n"
+	case setupScreen:
+		return m.setup.View(m.title) + "\n"
+	case initialsScreen:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			menuHeadingStyle.Render("New high score!"),
+			statLabelStyle.Render(fmt.Sprintf("%d points. Enter your initials:", m.score)),
+			m.initials.View(),
+			"",
+			footerStyle.Render("Press Enter to save, ESC to skip."),
+		) + "\n"
+	case highScoresScreen:
+		return m.highScores.View(m.newRank) + "\n\n" + footerStyle.Render("Press any key to go back.") + "\n"
 	}
 
-	// Combine the graphic and keyboard components
-	midView := lipgloss.JoinHorizontal(lipgloss.Center, m.graphicView.View(), keyboardElement)
+	// Build up pieces for top half of view
+	title, graphicElement, timerElement, keyboardElement := topPieces(&m)
+
+	// Combine the graphic, clock and keyboard components
+	midView := lipgloss.JoinHorizontal(lipgloss.Center, graphicElement, timerElement, keyboardElement)
 
 	// Format components together to be aligned
 	s := lipgloss.JoinVertical(
@@ -294,21 +1279,25 @@ func (m model) View() string {
 		midView,
 	)
 
+	// A little help, like a clue from player one
+	if m.hint != "" {
+		s += "\n\n" + hintStyle.Render(m.hint)
+	}
+
 	// Render the board where the word is revealed as player makes correct guess
-	// Wrap the tiles if the window is too small
-	board := m.board.View(" ")
-	if m.numCutoffTiles > 0 {
-		// Wrap effect is done by inserting newlines at the cutoff point
-		wrappedBoard := slices.Insert(m.board, len(m.board)-m.numCutoffTiles, PrettyString{"\n\n", lipgloss.NewStyle()})
-		board = wrappedBoard.View(" ")
+	// Wrap the tiles at word boundaries if the window is too small
+	var rows []string
+	for _, row := range m.board.Wrap(m.width, " ") {
+		rows = append(rows, row.View(" "))
 	}
+	board := strings.Join(rows, "\n\n")
 	s += "\n\n" + board
 
 	// Render the little input area for player guesses
 	s += "\n\n" + m.input.View()
 
 	// !: This is for debug :)
-	// s += fmt.Sprintf("\n\nPsst the word is %s\n\n", m.word)
+	// s += fmt.Sprintf("\n\nPsst the word is %s\n\n", m.game.Word())
 
 	s += "\n"
 
@@ -319,14 +1308,47 @@ func (m model) View() string {
 		s += m.notice.View()
 	}
 
+	// Today's result, ready to share
+	if m.gameOver && m.share != "" {
+		s += "\n\n" + shareStyle.Render(m.share) + "\n"
+	}
+
+	// Share the seed so someone else can play the same word
+	if m.gameOver && m.gameSeed() != 0 {
+		s += "\n" + tallyStyle.Render(fmt.Sprintf("Seed %d", m.gameSeed()))
+	}
+
+	// How the race is going
+	if m.countdown.mode == RaceTimer {
+		s += "\n" + tallyStyle.Render(fmt.Sprintf("Solved this race: %d", m.raceSolved))
+	}
+
+	// Keep score across games once there is something to show
+	if m.wins+m.losses > 0 {
+		s += "\n" + tallyStyle.Render(fmt.Sprintf("Wins: %d  Losses: %d", m.wins, m.losses))
+	}
+
 	s += "\n"
 
 	// footer
+	s += difficultyStyle.Render(gameKind(&m))
+	if m.scoring {
+		s += scoreStyle.Render(scoreText(&m))
+	}
 	s += m.footer.View()
 
 	return s
 }
 
+// The live score, and the streak bonus if there is one
+func scoreText(m *model) string {
+	text := fmt.Sprintf("Score %d", liveScore(m))
+	if m.streak > 0 && !m.runOver {
+		text += fmt.Sprintf(" · Streak %d (x%g)", m.streak, game.StreakMultiplier(m.streak))
+	}
+	return text
+}
+
 // ******************************************************************
 //
 //		Clear screen logic
@@ -363,17 +1385,28 @@ func ClearScreen() {
 	}
 }
 
+// Clear the screen, unless it's not ours to clear
+func (m model) clearScreen() {
+	if !m.remote {
+		ClearScreen()
+	}
+}
+
 // ******************************************************************
 //
 //	Run stuff
 //
 // ******************************************************************
-func Run() {
+func Run(opts Options) {
+	if opts.Theme.Name != "" {
+		ApplyTheme(opts.Theme)
+	}
+
 	// Wipe the current terminal of content for fresh play
 	ClearScreen()
 
-	// Start BubbleTea runtime
-	p := tea.NewProgram(initialModel())
+	// Start BubbleTea runtime. The mouse can click the keyboard.
+	p := tea.NewProgram(initialModel(opts), tea.WithMouseAllMotion())
 	if err := p.Start(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/braheezy/hangman/game"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func press(m model, keys ...string) model {
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	return m
+}
+
+func TestStartMenuSeed(t *testing.T) {
+	packs := []WordPack{{Name: "Fruit", Description: "Fruit", Words: []string{"apple", "banana", "cherry", "grape", "lemon", "mango"}}}
+	tests := []struct {
+		name string
+		keys []string
+		// The options that play the same word without the menu
+		replay Options
+	}{
+		{
+			name:   "no category",
+			keys:   []string{"enter"},
+			replay: Options{},
+		},
+		{
+			name:   "a category",
+			keys:   []string{"down", "enter"},
+			replay: Options{Category: "Fruit", Words: packs[0].Words},
+		},
+		{
+			name:   "skipping the menu",
+			keys:   []string{"esc"},
+			replay: Options{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := initialModel(Options{Difficulty: game.Medium, Seed: 1234, Packs: packs, StartMenu: true})
+			if m.screen != categoryScreen {
+				t.Fatal("the menu isn't showing")
+			}
+			m = press(m, tt.keys...)
+			if m.screen != gameScreen {
+				t.Fatal("no game after the menu")
+			}
+			if m.gameSeed() != 1234 {
+				t.Errorf("played seed %d, want 1234", m.gameSeed())
+			}
+
+			opts := tt.replay
+			opts.Difficulty = game.Medium
+			opts.Packs = packs
+			opts.Seed = m.gameSeed()
+			replay := initialModel(opts)
+			if replay.game.Word() != m.game.Word() {
+				t.Errorf("seed %d plays %s, then %s", m.gameSeed(), m.game.Word(), replay.game.Word())
+			}
+		})
+	}
+}
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestMouseClicks(t *testing.T) {
+	click := func(m model, msgs ...tea.MouseMsg) model {
+		for _, msg := range msgs {
+			next, _ := m.Update(msg)
+			m = next.(model)
+		}
+		return m
+	}
+	newGame := func() (model, int, int) {
+		m := initialModel(Options{Difficulty: game.Medium, Words: []string{"banana"}, Seed: 1})
+		next, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+		m = next.(model)
+		// Find E without drawing anything first
+		for y := 0; y < 50; y++ {
+			for x := 0; x < 120; x++ {
+				if letter, ok := m.keyboard.At(x, y); ok && letter == "E" {
+					return m, x, y
+				}
+			}
+		}
+		t.Fatal("no E on the keyboard")
+		return m, 0, 0
+	}
+
+	m, x, y := newGame()
+	// It's where View draws it
+	lines := strings.Split(ansi.ReplaceAllString(m.View(), ""), "\n")
+	if y >= len(lines) || len([]rune(lines[y])) <= x || !strings.HasPrefix(strings.TrimLeft(string([]rune(lines[y])[x:]), " "), "E") {
+		t.Fatalf("E isn't drawn at %d,%d", x, y)
+	}
+
+	m = click(m, tea.MouseMsg{Type: tea.MouseLeft, X: x, Y: y}, tea.MouseMsg{Type: tea.MouseRelease, X: x, Y: y})
+	if !m.keyboard.Used("E") || m.game.Misses() != 1 {
+		t.Errorf("clicking E: used = %v, %d misses", m.keyboard.Used("E"), m.game.Misses())
+	}
+
+	// Dragging off a letter doesn't guess anything
+	m, x, y = newGame()
+	r, ok := m.keyboard.At(x+4, y)
+	if !ok || r != "R" {
+		t.Fatalf("%q is next to E, want R", r)
+	}
+	m = click(m, tea.MouseMsg{Type: tea.MouseLeft, X: x, Y: y}, tea.MouseMsg{Type: tea.MouseLeft, X: x + 4, Y: y}, tea.MouseMsg{Type: tea.MouseRelease, X: x + 4, Y: y})
+	if m.keyboard.Used("E") || m.keyboard.Used("R") || m.game.Misses() != 0 {
+		t.Error("a drag made a guess")
+	}
+}
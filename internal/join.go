@@ -0,0 +1,304 @@
+package internal
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ******************************************************************
+//
+//	Join stuff
+//
+// The `hangman join` command: play on someone else's server. The
+// server keeps the game, this just draws it and sends guesses.
+// ******************************************************************
+
+// A connection to a hangman server
+type Client struct {
+	conn   net.Conn
+	reader *MessageReader
+}
+
+// Connect to a server and say hello
+func Dial(addr string, name string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn, reader: NewMessageReader(conn)}
+	if err := c.Send(Message{Type: MsgHello, Name: name}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) Send(msg Message) error {
+	return WriteMessage(c.conn, msg)
+}
+
+// The next message from the server
+func (c *Client) Read() (Message, error) {
+	return c.reader.Read()
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// A message from the server
+type serverMsg Message
+
+// The server hung up
+type disconnectedMsg struct{ err error }
+
+// Wait for the next message from the server
+func (c *Client) listen() tea.Cmd {
+	return func() tea.Msg {
+		msg, err := c.Read()
+		if err != nil {
+			return disconnectedMsg{err}
+		}
+		return serverMsg(msg)
+	}
+}
+
+// ******************************************************************
+//
+//	Join model
+//
+// ******************************************************************
+type joinModel struct {
+	client *Client
+	// How turns work
+	mode string
+	// The last state from the server. Nil until the first one comes.
+	state *GameState
+	// Drawn from the state
+	board       Board
+	keyboard    *Keyboard
+	graphicView *GraphicView
+	// Where guesses are typed
+	input   textinput.Model
+	solving bool
+	notice  PrettyString
+	title   PrettyString
+	footer  PrettyString
+	width   int
+	// The server hung up
+	gone bool
+}
+
+func newJoinModel(c *Client) joinModel {
+	graphicView := NewGraphicView()
+	keyboard := NewKeyboard()
+	return joinModel{
+		client:      c,
+		keyboard:    &keyboard,
+		graphicView: &graphicView,
+		input:       newInput(),
+		notice:      NewNotice(),
+		title:       NewTitle(),
+		footer:      NewFooter(),
+	}
+}
+
+func (m joinModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.client.listen())
+}
+
+// Redraw everything from a new state, and say what just happened
+func applyState(m *joinModel, state *GameState) {
+	guessed := 0
+	if m.state != nil {
+		guessed = len(m.state.Guesses)
+	}
+	m.state = state
+
+	m.board = NewPatternBoard(state.Pattern)
+	keyboard := NewKeyboard()
+	for _, g := range state.Guesses {
+		keyboard.FlipOn(g.Guess)
+	}
+	m.keyboard = &keyboard
+	m.graphicView.SetFrame(FrameFor(state.Misses, state.MaxMisses))
+
+	// Someone guessed. Flash like a local game would.
+	if last, ok := state.LastGuess(); ok && len(state.Guesses) > guessed {
+		m.graphicView.flash = true
+		m.notice.style = noticeStyle
+		if last.Hit {
+			m.graphicView.flashStyle = flashCorrectStyle
+			m.notice.text = fmt.Sprintf("%s guessed %s. Got it!", last.Player, last.Guess)
+		} else {
+			m.graphicView.flashStyle = flashWrongStyle
+			m.notice.text = fmt.Sprintf("%s guessed %s. Nope!", last.Player, last.Guess)
+		}
+	} else if len(state.Guesses) < guessed || len(state.Guesses) == 0 {
+		// A new word
+		m.notice.text = ""
+	}
+
+	switch state.Status {
+	case "won":
+		m.notice.text = fmt.Sprintf("Woo you all win! It was %s", state.Word)
+		m.notice.style = winNoticeStyle
+		m.footer.text = "Press Enter for the next word, ESC to leave."
+		m.input.Blur()
+	case "lost":
+		m.notice.text = fmt.Sprintf("You all lose :(\nThe hidden word was: %s", state.Word)
+		m.notice.style = loseNoticeStyle
+		m.footer.text = "Press Enter for the next word, ESC to leave."
+		m.input.Blur()
+	default:
+		m.input.Focus()
+		switch {
+		case state.MyTurn() && state.Turn != "":
+			m.footer.text = "Your turn! " + joinFooterText
+		case state.MyTurn():
+			m.footer.text = joinFooterText
+		default:
+			m.footer.text = fmt.Sprintf("Waiting for %s to guess.", state.Turn)
+		}
+	}
+}
+
+// What the footer says while guessing
+var joinFooterText = "Press / to solve the whole word, ESC to leave."
+
+func (m joinModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.graphicView.flash {
+		m.graphicView.ResetFlash()
+	}
+
+	switch msg := msg.(type) {
+	case serverMsg:
+		switch msg.Type {
+		case MsgWelcome:
+			m.mode = msg.Mode
+		case MsgState:
+			if msg.State != nil {
+				applyState(&m, msg.State)
+			}
+		case MsgError:
+			m.notice.text = msg.Error
+			m.notice.style = loseNoticeStyle
+		}
+		return m, m.client.listen()
+
+	case disconnectedMsg:
+		m.gone = true
+		m.notice.text = fmt.Sprintf("Lost the server: %v", msg.err)
+		m.notice.style = loseNoticeStyle
+		m.footer.text = "Press any key to quit."
+		m.input.Blur()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		if m.gone {
+			return m, tea.Quit
+		}
+		over := m.state != nil && m.state.Status != "playing"
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.solving {
+				m.solving = false
+				m.input = newInput()
+				return m, textinput.Blink
+			}
+			return m, tea.Quit
+		case "/":
+			if !m.solving && !over {
+				m.solving = true
+				m.input = newSolveInput()
+				return m, textinput.Blink
+			}
+		case "enter":
+			if over {
+				m.client.Send(Message{Type: MsgNext})
+				return m, nil
+			}
+			if m.input.Value() == "" {
+				return m, nil
+			}
+			m.client.Send(Message{Type: MsgGuess, Guess: m.input.Value()})
+			if m.solving {
+				m.solving = false
+				m.input = newInput()
+			}
+			m.input.Reset()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m joinModel) View() string {
+	if m.state == nil {
+		s := "Waiting for the server..."
+		if m.notice.text != "" {
+			s += "\n" + m.notice.View()
+		}
+		return s + "\n"
+	}
+
+	midView := lipgloss.JoinHorizontal(lipgloss.Center, m.graphicView.View(), m.keyboard.View())
+	s := lipgloss.JoinVertical(lipgloss.Center, m.title.View(), midView)
+
+	// Who's here, and whose go it is
+	var players []string
+	for _, p := range m.state.Players {
+		label := p
+		if p == m.state.You {
+			label += " (you)"
+		}
+		if p == m.state.Turn {
+			label = "▸" + label
+		}
+		players = append(players, label)
+	}
+	s += "\n\n" + hintStyle.Render("Players: "+strings.Join(players, ", "))
+
+	var rows []string
+	for _, row := range m.board.Wrap(m.width, " ") {
+		rows = append(rows, row.View(" "))
+	}
+	s += "\n\n" + strings.Join(rows, "\n\n")
+	s += "\n\n" + m.input.View() + "\n"
+
+	if m.notice.text != "" {
+		s += m.notice.View()
+	}
+	footer := m.footer
+	if m.solving {
+		footer.text = solveFooterText
+	}
+	s += "\n" + difficultyStyle.Render(m.mode) + footer.View()
+	return s
+}
+
+// Join a game on a server and play until the player leaves
+func Join(addr string, name string) error {
+	c, err := Dial(addr, name)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ClearScreen()
+	p := tea.NewProgram(newJoinModel(c))
+	return p.Start()
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	JSON lines stuff
+//
+// `hangman --protocol=jsonl`: the game for bots. Every change is one
+// JSON event on its own line on stdout, and every line on stdin is a
+// command:
+//
+//	{"guess":"E"}     guess a letter
+//	{"guess":"WORD"}  or solve the whole word
+//
+// Games follow each other until stdin is closed.
+// ******************************************************************
+const (
+	// A new word: {"length","lives","board",...}
+	EventGameStart = "game_start"
+	// What a guess did: {"guess","hit","positions","board",...}
+	EventGuessResult = "guess_result"
+	// The word is solved or the lives ran out: {"won","word",...}
+	EventGameOver = "game_over"
+	// A command that couldn't be played: {"code","error"}
+	EventError = "error"
+)
+
+// Error codes, so bots don't need to read the messages
+const (
+	// The line wasn't a command
+	ErrCodeBadCommand = "bad_command"
+	// Not a letter, or not a word
+	ErrCodeInvalidGuess   = "invalid_guess"
+	ErrCodeAlreadyGuessed = "already_guessed"
+	// Today's puzzle has been played, so there's no game at all
+	ErrCodeAlreadyPlayed = "already_played"
+)
+
+// A line from stdin
+type Command struct {
+	Guess string `json:"guess"`
+}
+
+type GameStartEvent struct {
+	Event string `json:"event"`
+	// Counts up from 1
+	Game int `json:"game"`
+	// How many tiles are on the board, counting the spaces and
+	// punctuation in phrases, so positions are indexes into board
+	Length int `json:"length"`
+	// How many misses it takes to lose
+	Lives int    `json:"lives"`
+	Board string `json:"board"`
+	// Zero for the daily puzzle
+	Seed     int64  `json:"seed,omitempty"`
+	Category string `json:"category,omitempty"`
+	Daily    int    `json:"daily,omitempty"`
+}
+
+type GuessResultEvent struct {
+	Event string `json:"event"`
+	Game  int    `json:"game"`
+	// The guess, in upper case
+	Guess string `json:"guess"`
+	Hit   bool   `json:"hit"`
+	Solve bool   `json:"solve,omitempty"`
+	// Where the guess uncovered letters. Empty on a miss.
+	Positions []int  `json:"positions"`
+	Board     string `json:"board"`
+	Misses    int    `json:"misses"`
+	LivesLeft int    `json:"lives_left"`
+}
+
+type GameOverEvent struct {
+	Event   string   `json:"event"`
+	Game    int      `json:"game"`
+	Won     bool     `json:"won"`
+	Word    string   `json:"word"`
+	Guesses []string `json:"guesses"`
+	Misses  int      `json:"misses"`
+	Seed    int64    `json:"seed,omitempty"`
+	// The daily puzzle summary
+	Share string `json:"share,omitempty"`
+}
+
+type ErrorEvent struct {
+	Event string `json:"event"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+	// What was sent, if it got that far
+	Guess string `json:"guess,omitempty"`
+	// For already_played, the puzzle and its summary. The summary is
+	// empty if it was quit part way through.
+	Daily int    `json:"daily,omitempty"`
+	Share string `json:"share,omitempty"`
+}
+
+// Tell a bot today's puzzle has been played already, instead of
+// playing it
+func WriteAlreadyPlayed(w io.Writer, state DailyState) error {
+	msg := "today's puzzle has already been played"
+	if state.Summary == "" {
+		msg = "today's puzzle was already started and quit part way through"
+	}
+	return json.NewEncoder(w).Encode(ErrorEvent{
+		Event: EventError,
+		Code:  ErrCodeAlreadyPlayed,
+		Error: msg,
+		Daily: state.Number,
+		Share: state.Summary,
+	})
+}
+
+// Play games reading commands from r and writing events to w, until r
+// runs out
+func RunJSONL(r io.Reader, w io.Writer, opts Options) error {
+	h := newHeadless(opts)
+	lines := bufio.NewScanner(r)
+	events := json.NewEncoder(w)
+
+	for number := 1; ; number++ {
+		g, seed, err := h.newGame()
+		if err != nil {
+			return err
+		}
+		started := time.Now()
+		err = events.Encode(GameStartEvent{
+			Event:    EventGameStart,
+			Game:     number,
+			Length:   utf8.RuneCountInString(g.Pattern()),
+			Lives:    g.MaxMisses(),
+			Board:    g.Pattern(),
+			Seed:     seed,
+			Category: opts.Category,
+			Daily:    opts.Daily,
+		})
+		if err != nil {
+			return err
+		}
+
+		for g.Status() == game.Playing {
+			if !lines.Scan() {
+				return lines.Err()
+			}
+			if err := playCommand(h, g, number, lines.Text(), events); err != nil {
+				return err
+			}
+		}
+
+		share, err := h.finish(g, seed, started)
+		if err != nil {
+			return err
+		}
+		guesses := g.Guesses()
+		if guesses == nil {
+			guesses = []string{}
+		}
+		err = events.Encode(GameOverEvent{
+			Event:   EventGameOver,
+			Game:    number,
+			Won:     g.Won(),
+			Word:    g.Word(),
+			Guesses: guesses,
+			Misses:  g.Misses(),
+			Seed:    seed,
+			Share:   share,
+		})
+		if err != nil || opts.Daily > 0 {
+			return err
+		}
+	}
+}
+
+// Play one line from stdin and write what happened. Only writing can
+// fail; bad commands are reported as events.
+func playCommand(h *headless, g *game.Game, number int, line string, events *json.Encoder) error {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	var cmd Command
+	if err := json.Unmarshal([]byte(line), &cmd); err != nil {
+		return events.Encode(ErrorEvent{Event: EventError, Code: ErrCodeBadCommand, Error: err.Error()})
+	}
+	guess := strings.TrimSpace(cmd.Guess)
+	if guess == "" {
+		return events.Encode(ErrorEvent{Event: EventError, Code: ErrCodeBadCommand, Error: `expected {"guess":"E"}`})
+	}
+
+	result, err := h.guess(g, guess)
+	switch {
+	case errors.Is(err, errNotAGuess):
+		return events.Encode(ErrorEvent{Event: EventError, Code: ErrCodeInvalidGuess, Error: err.Error(), Guess: guess})
+	case errors.Is(err, game.ErrAlreadyGuessed):
+		return events.Encode(ErrorEvent{Event: EventError, Code: ErrCodeAlreadyGuessed, Error: err.Error(), Guess: guess})
+	case err != nil:
+		return events.Encode(ErrorEvent{Event: EventError, Code: ErrCodeInvalidGuess, Error: err.Error(), Guess: guess})
+	}
+
+	positions := result.Positions
+	if positions == nil {
+		positions = []int{}
+	}
+	return events.Encode(GuessResultEvent{
+		Event:     EventGuessResult,
+		Game:      number,
+		Guess:     result.Letter,
+		Hit:       result.Hit(),
+		Solve:     result.Solve,
+		Positions: positions,
+		Board:     g.Pattern(),
+		Misses:    g.Misses(),
+		LivesLeft: g.MaxMisses() - g.Misses(),
+	})
+}
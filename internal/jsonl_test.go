@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Play input and split what comes out into events
+func runJSONL(t *testing.T, input string, opts Options) []json.RawMessage {
+	t.Helper()
+	var out bytes.Buffer
+	if err := RunJSONL(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+	var events []json.RawMessage
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var e json.RawMessage
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// Decode an event of the type want, checking its event field
+func decodeEvent(t *testing.T, raw json.RawMessage, name string, v interface{}) {
+	t.Helper()
+	var e struct{ Event string }
+	json.Unmarshal(raw, &e)
+	if e.Event != name {
+		t.Fatalf("got %s, want a %s event", raw, name)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunJSONL(t *testing.T) {
+	input := strings.Join([]string{
+		`{"guess":"a"}`,
+		`{"guess":"z"}`,
+		`{"guess":"A"}`,
+		`{"guess":"1"}`,
+		`not json`,
+		`{}`,
+		``,
+		`{"guess":"banana"}`,
+	}, "\n")
+	events := runJSONL(t, input, bananaOptions())
+	// The last is the next game starting
+	if len(events) != 10 {
+		t.Fatalf("got %d events, want 10: %s", len(events), events)
+	}
+
+	var start GameStartEvent
+	decodeEvent(t, events[0], EventGameStart, &start)
+	want := GameStartEvent{Event: EventGameStart, Game: 1, Length: 6, Lives: 8, Board: "______", Seed: 1}
+	if start != want {
+		t.Errorf("game_start = %+v, want %+v", start, want)
+	}
+
+	var hit GuessResultEvent
+	decodeEvent(t, events[1], EventGuessResult, &hit)
+	wantHit := GuessResultEvent{Event: EventGuessResult, Game: 1, Guess: "A", Hit: true, Positions: []int{1, 3, 5}, Board: "_A_A_A", LivesLeft: 8}
+	if !reflect.DeepEqual(hit, wantHit) {
+		t.Errorf("hit = %+v, want %+v", hit, wantHit)
+	}
+	var miss GuessResultEvent
+	decodeEvent(t, events[2], EventGuessResult, &miss)
+	wantMiss := GuessResultEvent{Event: EventGuessResult, Game: 1, Guess: "Z", Positions: []int{}, Board: "_A_A_A", Misses: 1, LivesLeft: 7}
+	if !reflect.DeepEqual(miss, wantMiss) {
+		t.Errorf("miss = %+v, want %+v", miss, wantMiss)
+	}
+
+	// Nothing that goes wrong costs a life. The blank line is ignored.
+	for i, code := range []string{ErrCodeAlreadyGuessed, ErrCodeInvalidGuess, ErrCodeBadCommand, ErrCodeBadCommand} {
+		var e ErrorEvent
+		decodeEvent(t, events[3+i], EventError, &e)
+		if e.Code != code || e.Error == "" {
+			t.Errorf("error %d = %+v, want code %s", i, e, code)
+		}
+	}
+
+	var solve GuessResultEvent
+	decodeEvent(t, events[7], EventGuessResult, &solve)
+	if !solve.Solve || !solve.Hit || solve.Board != "BANANA" || solve.Misses != 1 {
+		t.Errorf("solve = %+v", solve)
+	}
+	var over GameOverEvent
+	decodeEvent(t, events[8], EventGameOver, &over)
+	wantOver := GameOverEvent{Event: EventGameOver, Game: 1, Won: true, Word: "BANANA", Guesses: []string{"A", "Z", "BANANA"}, Misses: 1, Seed: 1}
+	if !reflect.DeepEqual(over, wantOver) {
+		t.Errorf("game_over = %+v, want %+v", over, wantOver)
+	}
+}
+
+func TestRunJSONLNextGame(t *testing.T) {
+	events := runJSONL(t, `{"guess":"banana"}`+"\n", bananaOptions())
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4: %s", len(events), events)
+	}
+	var next GameStartEvent
+	decodeEvent(t, events[3], EventGameStart, &next)
+	if next.Game != 2 || next.Seed == 1 {
+		t.Errorf("second game_start = %+v", next)
+	}
+}
+
+func TestRunJSONLPhraseLength(t *testing.T) {
+	opts := bananaOptions()
+	opts.Words = []string{"rock 'n' roll"}
+	var start GameStartEvent
+	decodeEvent(t, runJSONL(t, "", opts)[0], EventGameStart, &start)
+	if start.Length != 13 || start.Board != "____ '_' ____" {
+		t.Errorf("game_start = %+v, want the whole board counted", start)
+	}
+}
+
+func TestRunJSONLDaily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.json")
+	opts := Options{Difficulty: DailyDifficulty, Daily: 7, DailyPath: path}
+	// Anything after the daily game is over is ignored
+	events := runJSONL(t, `{"guess":"`+DailyWord(7)+`"}`+"\n"+`{"guess":"a"}`+"\n", opts)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: %s", len(events), events)
+	}
+	var start GameStartEvent
+	decodeEvent(t, events[0], EventGameStart, &start)
+	if start.Daily != 7 || start.Seed != 0 {
+		t.Errorf("game_start = %+v", start)
+	}
+	var over GameOverEvent
+	decodeEvent(t, events[2], EventGameOver, &over)
+	if !over.Won || !strings.HasPrefix(over.Share, "Hangman #7 ✅") {
+		t.Errorf("game_over = %+v", over)
+	}
+
+	state, err := LoadDaily(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Number != 7 || state.Summary != over.Share {
+		t.Errorf("saved %+v", state)
+	}
+
+	var out bytes.Buffer
+	if err := WriteAlreadyPlayed(&out, state); err != nil {
+		t.Fatal(err)
+	}
+	var e ErrorEvent
+	decodeEvent(t, out.Bytes(), EventError, &e)
+	if e.Code != ErrCodeAlreadyPlayed || e.Daily != 7 || e.Share != over.Share {
+		t.Errorf("already played = %+v", e)
+	}
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("already played isn't one line: %q", out.String())
+	}
+
+	// Quit part way through, there's no summary to share
+	out.Reset()
+	WriteAlreadyPlayed(&out, DailyState{Number: 7})
+	e = ErrorEvent{}
+	decodeEvent(t, out.Bytes(), EventError, &e)
+	if e.Code != ErrCodeAlreadyPlayed || e.Share != "" || !strings.Contains(e.Error, "quit") {
+		t.Errorf("already started = %+v", e)
+	}
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ******************************************************************
+//
+//	Word pack stuff
+//
+// A word pack is a themed word list, like animals or movies. It's a
+// regular word list file with a name and description in its header:
+//
+//	# name: Animals
+//	# description: Creatures great and small
+//	AARDVARK
+//	...
+//
+// Some packs are built in and more can be dropped in
+// $XDG_CONFIG_HOME/hangman/packs/
+// ******************************************************************
+type WordPack struct {
+	Name        string
+	Description string
+	Words       []string
+}
+
+//go:embed packs/*.txt
+var packFiles embed.FS
+
+// Where custom packs are looked for
+func packsDir() string {
+	return filepath.Join(configDir(), "packs")
+}
+
+// Read a pack. The file name is the fallback name.
+func ParsePack(data []byte, filename string) (WordPack, error) {
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	pack := WordPack{Name: base}
+	if base != "" {
+		pack.Name = strings.ToUpper(base[:1]) + base[1:]
+	}
+
+	// Pull the name and description out of the header comments
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "#")), ":")
+		if !found {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name":
+			pack.Name = strings.TrimSpace(value)
+		case "description":
+			pack.Description = strings.TrimSpace(value)
+		}
+	}
+
+	words, err := ParseWords(bytes.NewReader(data), filename)
+	if err != nil {
+		return WordPack{}, err
+	}
+	if len(words) == 0 {
+		return WordPack{}, fmt.Errorf("%s: no words found", filename)
+	}
+	pack.Words = words
+	return pack, nil
+}
+
+// The packs that ship with hangman
+func builtinPacks() []WordPack {
+	var packs []WordPack
+	names, _ := packFiles.ReadDir("packs")
+	for _, entry := range names {
+		data, _ := packFiles.ReadFile("packs/" + entry.Name())
+		// These are checked in and known good
+		pack, _ := ParsePack(data, entry.Name())
+		packs = append(packs, pack)
+	}
+	return packs
+}
+
+// Every pack: built-in ones, then custom ones sorted by name.
+// A custom pack with the same name as a built-in one replaces it.
+func LoadPacks() ([]WordPack, error) {
+	packs := builtinPacks()
+
+	entries, err := os.ReadDir(packsDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var custom []WordPack
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(packsDir(), e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		pack, err := ParsePack(data, path)
+		if err != nil {
+			return nil, err
+		}
+		custom = append(custom, pack)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+
+	for _, c := range custom {
+		replaced := false
+		for i, p := range packs {
+			if strings.EqualFold(p.Name, c.Name) {
+				packs[i] = c
+				replaced = true
+			}
+		}
+		if !replaced {
+			packs = append(packs, c)
+		}
+	}
+	return packs, nil
+}
+
+// Find a pack by name, ignoring case
+func FindPack(packs []WordPack, name string) (WordPack, error) {
+	var names []string
+	for _, p := range packs {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+		names = append(names, strings.ToLower(p.Name))
+	}
+	return WordPack{}, fmt.Errorf("unknown category %q (available: %s)", name, strings.Join(names, ", "))
+}
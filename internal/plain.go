@@ -0,0 +1,302 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	Plain stuff
+//
+// The game without the TUI. The board, misses and guesses are printed
+// a line at a time and guesses are read a line at a time, so it works
+// with screen readers, pipes and scripts.
+// ******************************************************************
+
+// The bookkeeping for games played without the TUI: picking words,
+// and saving stats and replays once they're over
+type headless struct {
+	opts Options
+	pool []string
+	// The seed for the next word
+	next int64
+	// The game's moves so far, if it's being recorded
+	moves []Move
+}
+
+func newHeadless(opts Options) *headless {
+	words := dictionary
+	if len(opts.Words) > 0 {
+		words = opts.Words
+	}
+	return &headless{
+		opts: opts,
+		pool: wordPool(words, opts.Difficulty),
+		next: opts.Seed,
+	}
+}
+
+// Start the next game. The seed is 0 for the daily puzzle, which is
+// marked as played as soon as it starts so quitting doesn't get you
+// another go.
+func (h *headless) newGame() (*game.Game, int64, error) {
+	h.moves = nil
+	if h.opts.Daily > 0 {
+		word := DailyWord(h.opts.Daily)
+		if err := SaveDaily(h.opts.DailyPath, DailyState{Number: h.opts.Daily}); err != nil {
+			return nil, 0, fmt.Errorf("couldn't save the daily puzzle: %w", err)
+		}
+		return newEngine(word, h.pool, h.opts.Difficulty, false, h.opts.SolvePenalty), 0, nil
+	}
+	seed := h.next
+	var word string
+	word, h.next = pickWord(h.pool, seed)
+	return newEngine(word, h.pool, h.opts.Difficulty, h.opts.Evil, h.opts.SolvePenalty), seed, nil
+}
+
+// What's being played, if it's worth saying
+func (h *headless) hint() string {
+	switch {
+	case h.opts.Daily > 0:
+		return fmt.Sprintf("Daily puzzle #%d", h.opts.Daily)
+	case h.opts.Category != "":
+		return "Category: " + h.opts.Category
+	}
+	return ""
+}
+
+// Record a finished game, unless it was practice, and save its replay.
+// For the daily puzzle, returns the summary to share too.
+func (h *headless) finish(g *game.Game, seed int64, started time.Time) (string, error) {
+	kind := h.opts.Difficulty.Name
+	switch {
+	case h.opts.Daily > 0:
+		kind = "Daily"
+	case h.opts.Evil:
+		kind += " (evil)"
+	}
+	if h.opts.StatsPath != "" && !h.opts.Practice {
+		err := RecordGame(h.opts.StatsPath, GameRecord{
+			Word:       g.Word(),
+			Guesses:    g.Guesses(),
+			Misses:     g.Misses(),
+			MaxMisses:  g.MaxMisses(),
+			Won:        g.Won(),
+			Difficulty: kind,
+			Category:   h.opts.Category,
+			Seed:       seed,
+			Started:    started,
+			DurationMS: time.Since(started).Milliseconds(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("couldn't save stats: %w", err)
+		}
+	}
+	if h.opts.ReplayDir != "" {
+		_, err := SaveReplay(h.opts.ReplayDir, Replay{
+			Word: g.Word(),
+			Seed: seed,
+			Options: ReplayOptions{
+				Difficulty:   kind,
+				MaxMisses:    g.MaxMisses(),
+				SolvePenalty: g.SolvePenalty(),
+				Evil:         h.opts.Evil && h.opts.Daily == 0,
+				Category:     h.opts.Category,
+				Daily:        h.opts.Daily,
+				Timer:        h.opts.Timer.String(),
+			},
+			Started: started,
+			Moves:   h.moves,
+			Won:     g.Won(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("couldn't save the replay: %w", err)
+		}
+	}
+	if h.opts.Daily == 0 {
+		return "", nil
+	}
+	share := ShareSummary(h.opts.Daily, g)
+	return share, SaveDaily(h.opts.DailyPath, DailyState{Number: h.opts.Daily, Summary: share})
+}
+
+// The guess was something you can't type in the input box
+var errNotAGuess = errors.New("not a letter or a word")
+
+// Check a guess like the input box would, then make it. More than one
+// letter is an attempt to solve.
+func makeGuess(g *game.Game, guess string) (game.Result, error) {
+	if err := validateGuess(guess); err != nil {
+		return game.Result{}, errNotAGuess
+	}
+	if utf8.RuneCountInString(guess) > 1 {
+		return g.Solve(guess)
+	}
+	return g.Guess(guess)
+}
+
+// Make a guess, noting it for the replay
+func (h *headless) guess(g *game.Game, guess string) (game.Result, error) {
+	result, err := makeGuess(g, guess)
+	if err == nil && h.opts.ReplayDir != "" {
+		kind := MoveGuess
+		if result.Solve {
+			kind = MoveSolve
+		}
+		h.moves = append(h.moves, Move{Kind: kind, Guess: result.Letter, At: time.Now()})
+	}
+	return result, err
+}
+
+// Take a hint, noting it for the replay
+func (h *headless) takeHint(g *game.Game) (game.LetterScore, error) {
+	hint, err := g.Hint(h.pool)
+	if err == nil && h.opts.ReplayDir != "" {
+		h.moves = append(h.moves, Move{Kind: MoveHint, Guess: hint.Letter, At: time.Now()})
+	}
+	return hint, err
+}
+
+// The board with a space between tiles, like _ A _ _ M A _
+func plainBoard(pattern string) string {
+	var tiles []string
+	for _, r := range pattern {
+		tiles = append(tiles, string(r))
+	}
+	return strings.Join(tiles, " ")
+}
+
+// Print what the player needs to make their next guess
+func printPlainState(w io.Writer, g *game.Game) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, plainBoard(g.Pattern()))
+	fmt.Fprintf(w, "Misses: %d/%d\n", g.Misses(), g.MaxMisses())
+	var guesses []string
+	for _, guess := range g.Guesses() {
+		// Quote attempts to solve so a phrase doesn't look like letters
+		if utf8.RuneCountInString(guess) > 1 {
+			guess = `"` + guess + `"`
+		}
+		guesses = append(guesses, guess)
+	}
+	guessed := strings.Join(guesses, " ")
+	if guessed == "" {
+		guessed = "none yet"
+	}
+	fmt.Fprintf(w, "Guessed: %s\n", guessed)
+}
+
+// Play games reading guesses from r and printing to w, until the
+// player stops or r runs out
+func RunPlain(r io.Reader, w io.Writer, opts Options) error {
+	h := newHeadless(opts)
+	lines := bufio.NewScanner(r)
+
+	for {
+		g, seed, err := h.newGame()
+		if err != nil {
+			return err
+		}
+		started := time.Now()
+
+		if hint := h.hint(); hint != "" {
+			fmt.Fprintln(w, hint)
+		}
+		if !playPlain(h, g, lines, w) {
+			// Out of guesses to read
+			return lines.Err()
+		}
+		if g.Won() {
+			fmt.Fprintf(w, "Woo you win! The word was %s\n", g.Word())
+		} else {
+			fmt.Fprintf(w, "You lose :( The hidden word was %s\n", g.Word())
+		}
+		if seed != 0 {
+			fmt.Fprintf(w, "Seed %d\n", seed)
+		}
+		share, err := h.finish(g, seed, started)
+		if err != nil {
+			return err
+		}
+
+		// One daily puzzle and that's it
+		if opts.Daily > 0 {
+			fmt.Fprintln(w, share)
+			return nil
+		}
+
+		fmt.Fprint(w, "Play again? [Y/n] ")
+		if !lines.Scan() {
+			fmt.Fprintln(w)
+			return lines.Err()
+		}
+		answer := strings.ToLower(strings.TrimSpace(lines.Text()))
+		if strings.HasPrefix(answer, "n") || strings.HasPrefix(answer, "q") {
+			return nil
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// Play one game. Returns false if the guesses ran out before it was over.
+func playPlain(h *headless, g *game.Game, lines *bufio.Scanner, w io.Writer) bool {
+	for g.Status() == game.Playing {
+		printPlainState(w, g)
+		fmt.Fprint(w, "Guess a letter, the whole word, or ? for a hint: ")
+		if !lines.Scan() {
+			fmt.Fprintln(w)
+			return false
+		}
+		guess := strings.TrimSpace(lines.Text())
+		if guess == "" {
+			continue
+		}
+		if guess == "?" {
+			hint, err := h.takeHint(g)
+			switch {
+			case errors.Is(err, game.ErrNoHintsLeft):
+				fmt.Fprintln(w, "No hints with one life left. You're on your own!")
+			case err != nil:
+				fmt.Fprintln(w, err)
+			default:
+				fmt.Fprintf(w, "Hint: try %s. That cost a life.\n", hint.Letter)
+			}
+			continue
+		}
+		result, err := h.guess(g, guess)
+		switch {
+		case errors.Is(err, errNotAGuess):
+			fmt.Fprintln(w, "That's not a guess. Try a letter")
+		case errors.Is(err, game.ErrAlreadyGuessed):
+			fmt.Fprintln(w, "Silly, you already guessed that! Try again")
+		case err != nil:
+			fmt.Fprintln(w, err)
+		case result.Hit() && result.Solve:
+			fmt.Fprintln(w, "You got it!")
+		case result.Hit():
+			fmt.Fprintf(w, "Yes! %s is in there %d %s\n", result.Letter, len(result.Positions), plural(len(result.Positions), "time"))
+		case result.Solve:
+			fmt.Fprintf(w, "Nope, it's not %s!\n", result.Letter)
+		default:
+			fmt.Fprintf(w, "Nope, no %s\n", result.Letter)
+		}
+	}
+	printPlainState(w, g)
+	return true
+}
+
+// The word, with an s if there's more than one
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
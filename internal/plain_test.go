@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// Options for games of BANANA and nothing else
+func bananaOptions() Options {
+	return Options{Difficulty: game.Medium, Words: []string{"banana"}, Seed: 1}
+}
+
+// Check that every line of want shows up in got, in order
+func checkTranscript(t *testing.T, got string, want []string) {
+	t.Helper()
+	rest := got
+	for _, line := range want {
+		i := strings.Index(rest, line)
+		if i < 0 {
+			t.Fatalf("missing %q after what came before in:\n%s", line, got)
+		}
+		rest = rest[i+len(line):]
+	}
+}
+
+func TestRunPlain(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "hit, miss, repeat and nonsense",
+			input: "a\nz\na\n1\n\nb\nn\n",
+			want: []string{
+				"_ _ _ _ _ _\nMisses: 0/8\nGuessed: none yet\n",
+				"Yes! A is in there 3 times\n",
+				"_ A _ A _ A\n",
+				"Nope, no Z\n",
+				"Misses: 1/8\nGuessed: A Z\n",
+				"Silly, you already guessed that! Try again\n",
+				"That's not a guess. Try a letter\n",
+				"Yes! B is in there 1 time\n",
+				"B A N A N A\nMisses: 1/8\nGuessed: A Z B N\n",
+				"Woo you win! The word was BANANA\nSeed 1\nPlay again? [Y/n] \n",
+			},
+		},
+		{
+			name:  "solving",
+			input: "a\nbanane split\nbanana\n",
+			want: []string{
+				"Nope, it's not BANANE SPLIT!\n",
+				"Misses: 2/8\nGuessed: A \"BANANE SPLIT\"\n",
+				"You got it!\n",
+				"Guessed: A \"BANANE SPLIT\" \"BANANA\"\n",
+				"Woo you win!",
+			},
+		},
+		{
+			name:  "hints",
+			input: "?\nc\nd\ne\nf\ng\nh\n?\ni\n",
+			want: []string{
+				"Hint: try A. That cost a life.\n",
+				"Misses: 1/8\n",
+				"Misses: 7/8\n",
+				"No hints with one life left. You're on your own!\n",
+				"Misses: 7/8\n",
+				"You lose :( The hidden word was BANANA\n",
+			},
+		},
+		{
+			name:  "playing again",
+			input: "banana\ny\nbanana\nn\n",
+			want: []string{
+				"Woo you win! The word was BANANA\nSeed 1\nPlay again? [Y/n] \n",
+				"Woo you win! The word was BANANA\nSeed ",
+				"Play again? [Y/n] ",
+			},
+		},
+		{
+			name:  "out of guesses part way",
+			input: "a\n",
+			want:  []string{"Guessed: A\nGuess a letter, the whole word, or ? for a hint: \n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := RunPlain(strings.NewReader(tt.input), &out, bananaOptions()); err != nil {
+				t.Fatal(err)
+			}
+			checkTranscript(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestRunPlainStopsAtNo(t *testing.T) {
+	var out strings.Builder
+	if err := RunPlain(strings.NewReader("banana\nn\nbanana\n"), &out, bananaOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "Woo you win!"); n != 1 {
+		t.Errorf("played %d games after saying no, want 1", n)
+	}
+}
@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ******************************************************************
+//
+//	Multiplayer protocol
+//
+// Players talk to a hangman server over TCP. Every message is one JSON
+// object on its own line, with a "type" saying what it is.
+//
+// From a player to the server:
+//
+//	{"type":"hello","name":"ada"}   first thing sent, joins the game
+//	{"type":"guess","guess":"E"}    guess a letter, or solve with a whole word
+//	{"type":"next"}                 start the next word once the game is over
+//
+// From the server to a player:
+//
+//	{"type":"welcome","name":"ada","mode":"turns"}
+//	    the name the player got (it's made unique) and how turns work
+//	{"type":"state","state":{...}}
+//	    everything needed to draw the game. Sent after every change.
+//	{"type":"error","error":"it's not your turn"}
+//	    the last message was refused. Nothing changed.
+// ******************************************************************
+
+// Message types
+const (
+	MsgHello   = "hello"
+	MsgGuess   = "guess"
+	MsgNext    = "next"
+	MsgWelcome = "welcome"
+	MsgState   = "state"
+	MsgError   = "error"
+)
+
+// A line that isn't a message. The connection is still fine.
+var ErrBadMessage = errors.New("bad message")
+
+// Every message has the same shape. Only the fields for its type are set.
+type Message struct {
+	Type  string     `json:"type"`
+	Name  string     `json:"name,omitempty"`
+	Mode  string     `json:"mode,omitempty"`
+	Guess string     `json:"guess,omitempty"`
+	Error string     `json:"error,omitempty"`
+	State *GameState `json:"state,omitempty"`
+}
+
+// The game as every player sees it
+type GameState struct {
+	// The board, with _ for letters not found yet
+	Pattern string `json:"pattern"`
+	// Every guess so far, in order
+	Guesses   []PlayerGuess `json:"guesses"`
+	Misses    int           `json:"misses"`
+	MaxMisses int           `json:"max_misses"`
+	// playing, won or lost
+	Status string `json:"status"`
+	// The word, only once the game is over
+	Word string `json:"word,omitempty"`
+	// Everyone playing, in turn order
+	Players []string `json:"players"`
+	// Whose turn it is. Empty in free-for-all.
+	Turn string `json:"turn,omitempty"`
+	// The player this state was sent to
+	You string `json:"you"`
+}
+
+// A guess and who made it
+type PlayerGuess struct {
+	Player string `json:"player"`
+	Guess  string `json:"guess"`
+	Hit    bool   `json:"hit"`
+}
+
+// The last guess made, if any
+func (s GameState) LastGuess() (PlayerGuess, bool) {
+	if len(s.Guesses) == 0 {
+		return PlayerGuess{}, false
+	}
+	return s.Guesses[len(s.Guesses)-1], true
+}
+
+// Is it this player's turn? Always true in free-for-all.
+func (s GameState) MyTurn() bool {
+	return s.Turn == "" || s.Turn == s.You
+}
+
+// Reads messages one line at a time
+type MessageReader struct {
+	scanner *bufio.Scanner
+}
+
+func NewMessageReader(r io.Reader) *MessageReader {
+	return &MessageReader{scanner: bufio.NewScanner(r)}
+}
+
+// The next message. io.EOF means the other side hung up.
+func (r *MessageReader) Read() (Message, error) {
+	var msg Message
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return msg, err
+		}
+		return msg, io.EOF
+	}
+	if err := json.Unmarshal(r.scanner.Bytes(), &msg); err != nil {
+		return msg, fmt.Errorf("%w: %v", ErrBadMessage, err)
+	}
+	return msg, nil
+}
+
+// Write one message as a line of JSON
+func WriteMessage(w io.Writer, msg Message) error {
+	// Encode adds the newline
+	return json.NewEncoder(w).Encode(msg)
+}
@@ -0,0 +1,384 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/braheezy/hangman/game"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ******************************************************************
+//
+//	Replay stuff
+//
+// With --replay-dir, every finished game is written out with its word,
+// seed, options and each move with the time it was made. `hangman
+// replay FILE` plays one back, move by move.
+// ******************************************************************
+
+// What a move did
+const (
+	MoveGuess   = "guess"
+	MoveSolve   = "solve"
+	MoveHint    = "hint"
+	MoveTimeout = "timeout"
+)
+
+// One thing the player did that changed the game
+type Move struct {
+	Kind string `json:"kind"`
+	// The letter or word guessed, or the letter a hint suggested
+	Guess string    `json:"guess,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// How the game was set up
+type ReplayOptions struct {
+	// The label from the stats, like Hard (evil)
+	Difficulty   string `json:"difficulty"`
+	MaxMisses    int    `json:"max_misses"`
+	SolvePenalty int    `json:"solve_penalty"`
+	Evil         bool   `json:"evil,omitempty"`
+	Category     string `json:"category,omitempty"`
+	Daily        int    `json:"daily,omitempty"`
+	Timer        string `json:"timer,omitempty"`
+}
+
+type Replay struct {
+	// The word at the end. An evil game's word is only settled by then,
+	// but it agrees with every guess so it plays back the same.
+	Word string `json:"word"`
+	// Zero if there's no seed, like player one's words
+	Seed    int64         `json:"seed,omitempty"`
+	Options ReplayOptions `json:"options"`
+	Started time.Time     `json:"started"`
+	Moves   []Move        `json:"moves"`
+	Won     bool          `json:"won"`
+}
+
+func LoadReplay(path string) (Replay, error) {
+	var r Replay
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("%s: %w", path, err)
+	}
+	if r.Word == "" || r.Options.MaxMisses <= 0 {
+		return r, fmt.Errorf("%s: not a hangman replay", path)
+	}
+	return r, nil
+}
+
+// Write a replay into dir, named for when the game started
+func SaveReplay(dir string, r Replay) (string, error) {
+	path := filepath.Join(dir, r.Started.Format("hangman-20060102-150405.000")+".json")
+	return path, writeReplay(path, r)
+}
+
+func writeReplay(path string, r Replay) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// A fresh game to play the moves on
+func (r Replay) newGame() *game.Game {
+	g := game.New(r.Word, r.Options.MaxMisses)
+	if r.Options.SolvePenalty > 0 {
+		g.SetSolvePenalty(r.Options.SolvePenalty)
+	}
+	return g
+}
+
+// Make a move on g, the way it was made the first time
+func (mv Move) play(g *game.Game) (game.Result, error) {
+	switch mv.Kind {
+	case MoveSolve:
+		return g.Solve(mv.Guess)
+	case MoveHint:
+		// The suggestion is already known, so no words are needed
+		_, err := g.Hint(nil)
+		return game.Result{}, err
+	case MoveTimeout:
+		return game.Result{}, g.TimeOut()
+	}
+	return g.Guess(mv.Guess)
+}
+
+// Note a move in the game being played
+func recordMove(m *model, kind string, guess string) {
+	if m.replayDir == "" {
+		return
+	}
+	m.moves = append(m.moves, Move{Kind: kind, Guess: guess, At: time.Now()})
+}
+
+// Save the finished game as a replay. A practice game can finish
+// again after an undo, and then its replay is written over.
+func saveReplay(m *model) {
+	if m.replayDir == "" {
+		return
+	}
+	r := Replay{
+		Word: m.game.Word(),
+		Seed: m.gameSeed(),
+		Options: ReplayOptions{
+			Difficulty:   gameKind(m),
+			MaxMisses:    m.game.MaxMisses(),
+			SolvePenalty: m.game.SolvePenalty(),
+			Evil:         m.evil && !m.twoPlayer && m.daily == 0,
+			Category:     m.category,
+			Daily:        m.daily,
+			Timer:        m.countdown.mode.String(),
+		},
+		Started: m.started,
+		Moves:   m.moves,
+		Won:     m.game.Won(),
+	}
+	var err error
+	if m.replayPath == "" {
+		m.replayPath, err = SaveReplay(m.replayDir, r)
+	} else {
+		err = writeReplay(m.replayPath, r)
+	}
+	if err != nil {
+		m.err = fmt.Errorf("couldn't save the replay: %w", err)
+	}
+}
+
+// ******************************************************************
+//
+//	Replay model
+//
+// ******************************************************************
+
+// The longest pause between moves. Nobody wants to watch someone think.
+const maxReplayPause = 3 * time.Second
+
+// How long the graphic flashes after a move
+const replayFlashTime = 300 * time.Millisecond
+
+// Time for the next move. Stale ones, from before a pause or a step,
+// are ignored.
+type replayStepMsg struct{ step int }
+
+// Time to stop flashing
+type replayUnflashMsg struct{}
+
+type replayModel struct {
+	replay Replay
+	game   *game.Game
+	// How many moves have been made
+	step    int
+	playing bool
+	// Drawn the same way as a real game
+	board       Board
+	keyboard    *Keyboard
+	graphicView *GraphicView
+	notice      PrettyString
+	title       PrettyString
+	footer      PrettyString
+	width       int
+}
+
+func newReplayModel(r Replay) replayModel {
+	m := replayModel{
+		replay:  r,
+		playing: true,
+		title:   NewTitle(),
+		footer:  NewFooter(),
+	}
+	rewindReplay(&m)
+	return m
+}
+
+// Back to before the first move
+func rewindReplay(m *replayModel) {
+	m.game = m.replay.newGame()
+	m.step = 0
+	m.board = NewPuzzleBoard(m.game.Word())
+	graphicView := NewGraphicView()
+	m.graphicView = &graphicView
+	keyboard := NewKeyboard()
+	m.keyboard = &keyboard
+	m.notice = NewNotice()
+}
+
+// Wait for the next move, as long as the player took (give or take)
+func (m replayModel) nextStep() tea.Cmd {
+	if !m.playing || m.step >= len(m.replay.Moves) {
+		return nil
+	}
+	last := m.replay.Started
+	if m.step > 0 {
+		last = m.replay.Moves[m.step-1].At
+	}
+	pause := m.replay.Moves[m.step].At.Sub(last)
+	if pause > maxReplayPause {
+		pause = maxReplayPause
+	}
+	if pause < replayFlashTime {
+		pause = replayFlashTime
+	}
+	step := m.step
+	return tea.Tick(pause, func(time.Time) tea.Msg { return replayStepMsg{step} })
+}
+
+// Make the next move and show it like the game did
+func stepReplay(m *replayModel) tea.Cmd {
+	if m.step >= len(m.replay.Moves) {
+		return nil
+	}
+	mv := m.replay.Moves[m.step]
+	m.step++
+	result, err := mv.play(m.game)
+	m.notice.style = noticeStyle
+	m.notice.text = ""
+
+	switch {
+	case err != nil:
+		// The file and the game disagree. Show it and carry on.
+		m.notice.text = fmt.Sprintf("%s %s: %v", mv.Kind, mv.Guess, err)
+	case mv.Kind == MoveHint:
+		m.notice.text = fmt.Sprintf("Hint: try %s", mv.Guess)
+		m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashWrongStyle
+	case mv.Kind == MoveTimeout:
+		m.notice.text = "Too slow! That cost a life."
+		m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashWrongStyle
+	case result.Hit():
+		pattern := []rune(m.game.Pattern())
+		for _, id := range result.Positions {
+			m.board[id].text = string(pattern[id])
+		}
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashCorrectStyle
+		if !result.Solve {
+			m.keyboard.FlipOn(result.Letter)
+		}
+	default:
+		m.graphicView.SetFrame(FrameFor(m.game.Misses(), m.game.MaxMisses()))
+		m.graphicView.flash = true
+		m.graphicView.flashStyle = flashWrongStyle
+		if result.Solve {
+			m.notice.text = fmt.Sprintf("Nope, it's not %s!", result.Letter)
+		} else {
+			m.keyboard.FlipOn(result.Letter)
+		}
+	}
+
+	switch m.game.Status() {
+	case game.Won:
+		m.notice.text = "Woo you win!"
+		m.notice.style = winNoticeStyle
+	case game.Lost:
+		m.notice.text = fmt.Sprintf("You lose :(\nThe hidden word was: %s", m.game.Word())
+		m.notice.style = loseNoticeStyle
+	}
+	if m.step >= len(m.replay.Moves) {
+		m.playing = false
+	}
+	return tea.Tick(replayFlashTime, func(time.Time) tea.Msg { return replayUnflashMsg{} })
+}
+
+func (m replayModel) Init() tea.Cmd {
+	return m.nextStep()
+}
+
+func (m replayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case replayStepMsg:
+		if !m.playing || msg.step != m.step {
+			return m, nil
+		}
+		return m, tea.Batch(stepReplay(&m), m.nextStep())
+
+	case replayUnflashMsg:
+		m.graphicView.ResetFlash()
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		case " ", "p":
+			if m.step >= len(m.replay.Moves) {
+				// Watch it again from the top
+				rewindReplay(&m)
+			}
+			m.playing = !m.playing
+			return m, m.nextStep()
+		case "right", "l", "n":
+			m.playing = false
+			return m, stepReplay(&m)
+		case "r", "home":
+			rewindReplay(&m)
+			return m, m.nextStep()
+		}
+	}
+	return m, nil
+}
+
+func (m replayModel) View() string {
+	midView := lipgloss.JoinHorizontal(lipgloss.Center, m.graphicView.View(), m.keyboard.View())
+	s := lipgloss.JoinVertical(lipgloss.Center, m.title.View(), midView)
+
+	// What was played, and when
+	about := fmt.Sprintf("Replay of %s", m.replay.Started.Format("Jan 2 2006 15:04"))
+	if m.replay.Options.Category != "" {
+		about += " · Category: " + m.replay.Options.Category
+	}
+	if m.replay.Seed != 0 {
+		about += fmt.Sprintf(" · Seed %d", m.replay.Seed)
+	}
+	s += "\n\n" + hintStyle.Render(about)
+
+	var rows []string
+	for _, row := range m.board.Wrap(m.width, " ") {
+		rows = append(rows, row.View(" "))
+	}
+	s += "\n\n" + strings.Join(rows, "\n\n") + "\n\n"
+
+	if m.notice.text != "" {
+		s += m.notice.View()
+	}
+	s += "\n" + tallyStyle.Render(fmt.Sprintf("Move %d of %d", m.step, len(m.replay.Moves))) + "\n"
+
+	footer := m.footer
+	switch {
+	case m.playing:
+		footer.text = "Press Space to pause, → to step, R to restart, ESC to quit."
+	case m.step >= len(m.replay.Moves):
+		footer.text = "Press Space to watch again, ESC to quit."
+	default:
+		footer.text = "Press Space to play, → to step, R to restart, ESC to quit."
+	}
+	s += difficultyStyle.Render(m.replay.Options.Difficulty) + footer.View()
+	return s
+}
+
+// Play back a replay file until the viewer quits
+func RunReplay(path string) error {
+	r, err := LoadReplay(path)
+	if err != nil {
+		return err
+	}
+	ClearScreen()
+	p := tea.NewProgram(newReplayModel(r))
+	return p.Start()
+}
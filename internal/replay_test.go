@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/braheezy/hangman/game"
+)
+
+func guessAll(m *model, guesses ...string) {
+	for _, g := range guesses {
+		if len(g) > 1 {
+			startSolving(m)
+		}
+		m.input.SetValue(g)
+		handleGuess(m)
+	}
+}
+
+// Every replay in dir
+func loadReplays(t *testing.T, dir string) []Replay {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replays []Replay
+	for _, path := range paths {
+		r, err := LoadReplay(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replays = append(replays, r)
+	}
+	return replays
+}
+
+// Play a replay's moves on a fresh game
+func playBack(t *testing.T, r Replay) *game.Game {
+	t.Helper()
+	g := r.newGame()
+	for _, mv := range r.Moves {
+		if _, err := mv.play(g); err != nil {
+			t.Fatalf("%s %s: %v", mv.Kind, mv.Guess, err)
+		}
+	}
+	return g
+}
+
+func TestSaveReplay(t *testing.T) {
+	dir := t.TempDir()
+	m := initialModel(Options{Difficulty: game.Medium, Words: []string{"banana"}, Seed: 1, ReplayDir: dir})
+	guessAll(&m, "a", "z")
+	if len(loadReplays(t, dir)) != 0 {
+		t.Fatal("a replay was saved before the game was over")
+	}
+	handleHint(&m)
+	guessAll(&m, "banana")
+
+	replays := loadReplays(t, dir)
+	if len(replays) != 1 {
+		t.Fatalf("%d replays, want 1", len(replays))
+	}
+	r := replays[0]
+	if r.Word != "BANANA" || r.Seed != 1 || !r.Won || len(r.Moves) != 4 || r.Moves[2].Kind != MoveHint {
+		t.Errorf("replay = %+v", r)
+	}
+	g := playBack(t, r)
+	if !g.Won() || g.Misses() != m.game.Misses() {
+		t.Errorf("played back to %+v, the game ended %+v", g.State(), m.game.State())
+	}
+
+	// The next game gets its own
+	resetGame(&m)
+	guessAll(&m, "banana")
+	if n := len(loadReplays(t, dir)); n != 2 {
+		t.Errorf("%d replays after two games, want 2", n)
+	}
+}
+
+// Undoing the end of a practice game and finishing it again leaves
+// one replay, of how it really ended
+func TestSaveReplayAfterUndo(t *testing.T) {
+	dir := t.TempDir()
+	m := initialModel(Options{Difficulty: game.Expert, Words: []string{"banana"}, Seed: 1, ReplayDir: dir, Practice: true})
+	guessAll(&m, "q", "w", "x", "z")
+	if !m.game.Lost() {
+		t.Fatalf("game = %+v, want it lost", m.game.State())
+	}
+	if r := loadReplays(t, dir); len(r) != 1 || r[0].Won {
+		t.Fatalf("replays after losing = %+v", r)
+	}
+
+	handleUndo(&m)
+	guessAll(&m, "banana")
+	replays := loadReplays(t, dir)
+	if len(replays) != 1 {
+		t.Fatalf("%d replays, want 1", len(replays))
+	}
+	if r := replays[0]; !r.Won || len(r.Moves) != 4 {
+		t.Errorf("replay = %+v, want the win", r)
+	}
+	if g := playBack(t, replays[0]); !g.Won() {
+		t.Errorf("played back to %+v", g.State())
+	}
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ******************************************************************
+//
+//	High score stuff
+//
+// A run's points keep adding up until a word is lost. The best runs
+// make it onto a little arcade-style leaderboard kept on disk.
+// ******************************************************************
+
+// How many scores the leaderboard keeps
+const MaxHighScores = 10
+
+type HighScore struct {
+	Initials   string    `json:"initials"`
+	Score      int       `json:"score"`
+	Difficulty string    `json:"difficulty"`
+	Date       time.Time `json:"date"`
+}
+
+// The leaderboard, best first
+type HighScores struct {
+	Scores []HighScore `json:"scores"`
+}
+
+// Where high scores are kept unless told otherwise
+func DefaultScoresPath() string {
+	return filepath.Join(dataDir(), "scores.json")
+}
+
+// Read the leaderboard. A missing file is an empty leaderboard.
+func LoadHighScores(path string) (HighScores, error) {
+	var h HighScores
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Write the leaderboard, creating directories as needed
+func SaveHighScores(path string, h HighScores) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// Would this score make the leaderboard?
+func (h HighScores) Qualifies(score int) bool {
+	if score <= 0 {
+		return false
+	}
+	return len(h.Scores) < MaxHighScores || score > h.Scores[len(h.Scores)-1].Score
+}
+
+// Put a score in its place and drop whatever falls off the bottom.
+// Ties go below the scores already there. Returns where the score
+// landed, or -1 if it didn't make it.
+func (h *HighScores) Add(s HighScore) int {
+	if !h.Qualifies(s.Score) {
+		return -1
+	}
+	i := 0
+	for i < len(h.Scores) && h.Scores[i].Score >= s.Score {
+		i++
+	}
+	h.Scores = append(h.Scores[:i], append([]HighScore{s}, h.Scores[i:]...)...)
+	if len(h.Scores) > MaxHighScores {
+		h.Scores = h.Scores[:MaxHighScores]
+	}
+	return i
+}
+
+// Add a score to the leaderboard file. The file is re-read first to
+// pick up scores other sessions have added.
+func AddHighScore(path string, s HighScore) (HighScores, int, error) {
+	keptFilesMu.Lock()
+	defer keptFilesMu.Unlock()
+	h, err := LoadHighScores(path)
+	if err != nil {
+		return h, -1, err
+	}
+	rank := h.Add(s)
+	if rank < 0 {
+		return h, rank, nil
+	}
+	return h, rank, SaveHighScores(path, h)
+}
+
+// The leaderboard as a table. The row at highlight stands out.
+func (h HighScores) View(highlight int) string {
+	lines := []string{menuHeadingStyle.Render("High scores")}
+	if len(h.Scores) == 0 {
+		lines = append(lines, statLabelStyle.Render("No high scores yet. Go set one!"))
+	}
+	for i, s := range h.Scores {
+		line := fmt.Sprintf("%2d. %-3s %7d  %-14s %s", i+1, s.Initials, s.Score, s.Difficulty, s.Date.Format("2006-01-02"))
+		if i == highlight {
+			lines = append(lines, menuSelectedStyle.Render(line))
+		} else {
+			lines = append(lines, menuItemStyle.Render(line))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// ******************************************************************
+//
+//	Initials entry
+//
+// ******************************************************************
+
+// How many letters of initials
+const maxInitials = 3
+
+func newInitialsInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "AAA"
+	ti.Focus()
+	ti.CharLimit = maxInitials
+	ti.Width = maxInitials
+	ti.Validate = validateInitials()
+	ti.Prompt = "─> "
+	ti.PromptStyle = lipgloss.NewStyle().
+		Foreground(secondaryColor)
+	ti.PlaceholderStyle = lipgloss.NewStyle().
+		Italic(true).
+		Faint(true).
+		Foreground(secondaryColor)
+	return ti
+}
+
+// Initials are letters only
+func validateInitials() textinput.ValidateFunc {
+	return func(s string) error {
+		for _, r := range s {
+			if !unicode.IsLetter(r) {
+				return errors.New("not valid input")
+			}
+		}
+		return nil
+	}
+}
+
+// The initials to save, defaulting to the placeholder
+func initialsOf(ti textinput.Model) string {
+	initials := strings.ToUpper(strings.TrimSpace(ti.Value()))
+	if initials == "" {
+		return ti.Placeholder
+	}
+	return initials
+}
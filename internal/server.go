@@ -0,0 +1,367 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	Server stuff
+//
+// The `hangman serve` command: one game shared by everyone connected.
+// The server owns the engine. Players only send guesses and draw
+// whatever state comes back. See protocol.go for the messages.
+// ******************************************************************
+
+// How players take turns
+type TurnMode int
+
+const (
+	// Players guess one after another, in the order they joined
+	TakeTurns TurnMode = iota
+	// Anyone can guess at any time
+	FreeForAll
+)
+
+// The names the --mode flag understands, in TurnMode order
+var turnModeNames = []string{"turns", "coop"}
+
+func (t TurnMode) String() string {
+	return turnModeNames[t]
+}
+
+// Look up a turn mode by name
+func ParseTurnMode(name string) (TurnMode, error) {
+	for i, n := range turnModeNames {
+		if strings.EqualFold(name, n) {
+			return TurnMode(i), nil
+		}
+	}
+	return TakeTurns, fmt.Errorf("unknown mode %q (choose from %s)", name, strings.Join(turnModeNames, ", "))
+}
+
+type ServerOptions struct {
+	Mode TurnMode
+	// Picks the words and the number of lives
+	Difficulty game.Difficulty
+	// Words to play instead of the dictionary
+	Words []string
+	// Seeds the words picked
+	Seed int64
+	// Where to log who comes and goes. Nil means don't.
+	Log *log.Logger
+}
+
+// How many messages can wait for a slow player before they're dropped
+const outboxSize = 32
+
+// Someone connected to the server
+type player struct {
+	name   string
+	conn   net.Conn
+	outbox chan Message
+}
+
+type Server struct {
+	mode  TurnMode
+	maxes int
+	words []string
+	rng   *rand.Rand
+	log   *log.Logger
+
+	// Guards everything below
+	mu       sync.Mutex
+	listener net.Listener
+	game     *game.Game
+	guesses  []PlayerGuess
+	players  []*player
+	// Index into players of whose turn it is
+	turn   int
+	closed bool
+}
+
+func NewServer(opts ServerOptions) *Server {
+	words := dictionary
+	if len(opts.Words) > 0 {
+		words = opts.Words
+	}
+	logger := opts.Log
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	s := &Server{
+		mode:  opts.Mode,
+		maxes: opts.Difficulty.MaxMisses,
+		words: wordPool(words, opts.Difficulty),
+		rng:   rand.New(rand.NewSource(opts.Seed)),
+		log:   logger,
+	}
+	s.newGame()
+	return s
+}
+
+// Listen on addr and serve players until the server is closed
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.log.Printf("serving hangman on %s (%s)", l.Addr(), s.mode)
+	return s.Serve(l)
+}
+
+// Serve players connecting to l until the server is closed
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return net.ErrClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Stop listening and hang up on everyone
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, p := range s.players {
+		p.conn.Close()
+	}
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+// Talk to one player until they hang up
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := NewMessageReader(conn)
+
+	// Nobody plays without saying hello
+	msg, err := reader.Read()
+	if err != nil {
+		return
+	}
+	if msg.Type != MsgHello {
+		WriteMessage(conn, Message{Type: MsgError, Error: "say hello first"})
+		return
+	}
+
+	p := &player{conn: conn, outbox: make(chan Message, outboxSize)}
+	go p.write()
+	defer close(p.outbox)
+
+	if !s.join(p, msg.Name) {
+		return
+	}
+	defer s.leave(p)
+
+	for {
+		msg, err := reader.Read()
+		if errors.Is(err, ErrBadMessage) {
+			p.send(Message{Type: MsgError, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return
+		}
+		switch msg.Type {
+		case MsgGuess:
+			s.guess(p, msg.Guess)
+		case MsgNext:
+			s.next(p)
+		default:
+			p.send(Message{Type: MsgError, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
+
+// Send everything in the outbox, in order
+func (p *player) write() {
+	for msg := range p.outbox {
+		if err := WriteMessage(p.conn, msg); err != nil {
+			p.conn.Close()
+		}
+	}
+}
+
+// Queue a message. A player too slow to keep up gets dropped.
+func (p *player) send(msg Message) {
+	select {
+	case p.outbox <- msg:
+	default:
+		p.conn.Close()
+	}
+}
+
+// Add a player, giving them a name nobody else has
+func (s *Server) join(p *player, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	p.name = s.uniqueName(name)
+	s.players = append(s.players, p)
+	s.log.Printf("%s joined from %s", p.name, p.conn.RemoteAddr())
+	p.send(Message{Type: MsgWelcome, Name: p.name, Mode: s.mode.String()})
+	s.broadcast()
+	return true
+}
+
+func (s *Server) uniqueName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "player"
+	}
+	taken := func(n string) bool {
+		for _, p := range s.players {
+			if p.name == n {
+				return true
+			}
+		}
+		return false
+	}
+	unique := name
+	for i := 2; taken(unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	return unique
+}
+
+// Remove a player, passing their turn on if it was theirs
+func (s *Server) leave(p *player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, other := range s.players {
+		if other != p {
+			continue
+		}
+		s.players = append(s.players[:i], s.players[i+1:]...)
+		// Everyone after them moves up one
+		if i < s.turn {
+			s.turn--
+		}
+		if s.turn >= len(s.players) {
+			s.turn = 0
+		}
+		break
+	}
+	s.log.Printf("%s left", p.name)
+	s.broadcast()
+}
+
+// A player guessed a letter, or tried to solve the word
+func (s *Server) guess(p *player, guess string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mode == TakeTurns && s.players[s.turn] != p {
+		p.send(Message{Type: MsgError, Error: fmt.Sprintf("it's not your turn, it's %s's", s.players[s.turn].name)})
+		return
+	}
+
+	var result game.Result
+	var err error
+	if len([]rune(strings.TrimSpace(guess))) > 1 {
+		result, err = s.game.Solve(guess)
+	} else {
+		result, err = s.game.Guess(guess)
+	}
+	if err != nil {
+		p.send(Message{Type: MsgError, Error: guessError(err)})
+		return
+	}
+	s.guesses = append(s.guesses, PlayerGuess{Player: p.name, Guess: result.Letter, Hit: result.Hit()})
+	if s.mode == TakeTurns {
+		s.turn = (s.turn + 1) % len(s.players)
+	}
+	s.broadcast()
+}
+
+// What to tell a player whose guess was refused
+func guessError(err error) string {
+	switch {
+	case errors.Is(err, game.ErrAlreadyGuessed):
+		return "that's already been guessed"
+	case errors.Is(err, game.ErrGameOver):
+		return "the game is over, send next to play again"
+	}
+	return err.Error()
+}
+
+// Anyone can start the next word once the game is over
+func (s *Server) next(p *player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.game.Status() == game.Playing {
+		p.send(Message{Type: MsgError, Error: "the game isn't over yet"})
+		return
+	}
+	s.newGame()
+	s.broadcast()
+}
+
+// Pick a new word. Turns carry on from where they were.
+func (s *Server) newGame() {
+	word := s.words[s.rng.Intn(len(s.words))]
+	s.game = game.New(word, s.maxes)
+	s.guesses = nil
+}
+
+// Send everyone the latest state
+func (s *Server) broadcast() {
+	state := s.state()
+	for _, p := range s.players {
+		mine := state
+		mine.You = p.name
+		p.send(Message{Type: MsgState, State: &mine})
+	}
+}
+
+// The state everyone sees, before it's addressed to anyone
+func (s *Server) state() GameState {
+	state := GameState{
+		Pattern:   s.game.Pattern(),
+		Guesses:   append([]PlayerGuess{}, s.guesses...),
+		Misses:    s.game.Misses(),
+		MaxMisses: s.game.MaxMisses(),
+		Status:    s.game.Status().String(),
+		Players:   []string{},
+	}
+	if s.game.Status() != game.Playing {
+		state.Word = s.game.Word()
+	}
+	for _, p := range s.players {
+		state.Players = append(state.Players, p.name)
+	}
+	if s.mode == TakeTurns && len(s.players) > 0 {
+		state.Turn = s.players[s.turn].name
+	}
+	return state
+}
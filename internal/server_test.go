@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// Start a server on a free port with only one word to pick
+func startServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(ServerOptions{Difficulty: game.Medium, Words: []string{"banana"}})
+	done := make(chan error)
+	go func() { done <- s.Serve(l) }()
+	t.Cleanup(func() {
+		s.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	return l.Addr().String()
+}
+
+func dial(t *testing.T, addr string, name string) *Client {
+	t.Helper()
+	c, err := Dial(addr, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// The next message, which has to be of type typ
+func expect(t *testing.T, c *Client, typ string) Message {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg, err := c.Read()
+	if err != nil {
+		t.Fatalf("waiting for %s: %v", typ, err)
+	}
+	if msg.Type != typ {
+		t.Fatalf("got %+v, want a %s", msg, typ)
+	}
+	return msg
+}
+
+func TestServer(t *testing.T) {
+	addr := startServer(t)
+
+	ada := dial(t, addr, "ada")
+	if msg := expect(t, ada, MsgWelcome); msg.Name != "ada" || msg.Mode != "turns" {
+		t.Errorf("welcome = %+v", msg)
+	}
+	state := expect(t, ada, MsgState).State
+	if state.Pattern != "______" || state.Turn != "ada" || state.You != "ada" {
+		t.Errorf("first state = %+v", state)
+	}
+
+	// Names are made unique
+	bob := dial(t, addr, "ada")
+	if msg := expect(t, bob, MsgWelcome); msg.Name != "ada2" {
+		t.Errorf("second player is called %q, want ada2", msg.Name)
+	}
+	expect(t, bob, MsgState)
+	state = expect(t, ada, MsgState).State
+	if want := []string{"ada", "ada2"}; !reflect.DeepEqual(state.Players, want) {
+		t.Errorf("players = %v, want %v", state.Players, want)
+	}
+
+	// Only the player whose turn it is can guess
+	bob.Send(Message{Type: MsgGuess, Guess: "a"})
+	if msg := expect(t, bob, MsgError); msg.Error != "it's not your turn, it's ada's" {
+		t.Errorf("out of turn guess: %q", msg.Error)
+	}
+
+	// Everyone sees a guess, and the turn moves on
+	ada.Send(Message{Type: MsgGuess, Guess: "a"})
+	for _, c := range []*Client{ada, bob} {
+		state := expect(t, c, MsgState).State
+		last, _ := state.LastGuess()
+		if state.Pattern != "_A_A_A" || last != (PlayerGuess{Player: "ada", Guess: "A", Hit: true}) || state.Turn != "ada2" {
+			t.Errorf("state after a guess = %+v", state)
+		}
+	}
+
+	// No skipping a word part way through
+	ada.Send(Message{Type: MsgNext})
+	if msg := expect(t, ada, MsgError); msg.Error != "the game isn't over yet" {
+		t.Errorf("next mid-game: %q", msg.Error)
+	}
+
+	// When the player whose turn it is leaves, it passes on
+	bob.Close()
+	state = expect(t, ada, MsgState).State
+	if !reflect.DeepEqual(state.Players, []string{"ada"}) || state.Turn != "ada" {
+		t.Errorf("state after ada2 left = %+v", state)
+	}
+	ada.Send(Message{Type: MsgGuess, Guess: "n"})
+	if state := expect(t, ada, MsgState).State; state.Pattern != "_ANANA" {
+		t.Errorf("pattern = %q, want _ANANA", state.Pattern)
+	}
+}
+
+func TestServerHelloFirst(t *testing.T) {
+	addr := startServer(t)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{conn: conn, reader: NewMessageReader(conn)}
+	defer c.Close()
+
+	c.Send(Message{Type: MsgGuess, Guess: "a"})
+	if msg := expect(t, c, MsgError); msg.Error != "say hello first" {
+		t.Errorf("guess before hello: %q", msg.Error)
+	}
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	Solve stuff
+//
+// The `hangman solve` command: rank the next letters for a board
+// ******************************************************************
+
+// Print the candidate count and the best letters for a pattern.
+// words defaults to the dictionary. top limits how many letters are
+// listed, zero means all of them.
+func PrintSolve(w io.Writer, pattern string, wrong string, words []string, top int) error {
+	if len(words) == 0 {
+		words = dictionary
+	}
+	// Be forgiving about how blanks are typed
+	pattern = strings.ToUpper(strings.NewReplacer("?", "_", ".", "_").Replace(pattern))
+
+	candidates := game.Candidates(pattern, wrong, words)
+	if len(candidates) == 0 {
+		return fmt.Errorf("no words fit %s", pattern)
+	}
+	fmt.Fprintf(w, "%d candidates\n", len(candidates))
+	if len(candidates) <= 10 {
+		fmt.Fprintf(w, "%s\n", strings.Join(candidates, " "))
+	}
+
+	// Letters showing on the board count as guessed too
+	guessed := wrong
+	for _, r := range pattern {
+		if r != game.Blank {
+			guessed += string(r)
+		}
+	}
+	ranked := game.RankLetters(candidates, guessed)
+	if top > 0 && len(ranked) > top {
+		ranked = ranked[:top]
+	}
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Letter\tBits\tWords")
+	for _, score := range ranked {
+		percent := float64(score.Count) / float64(len(candidates)) * 100
+		fmt.Fprintf(tw, "%s\t%.2f\t%d (%.0f%%)\n", score.Letter, score.Entropy, score.Count, percent)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gliderlabs/ssh"
+	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// ******************************************************************
+//
+//	SSH stuff
+//
+// The `hangman ssh` command: host the game so anyone can play with
+// just `ssh -p 2222 host`. Every session gets its own game, and
+// players are told apart by their public key.
+// ******************************************************************
+type SSHOptions struct {
+	Port int
+	// The server's private key. It's made if it doesn't exist yet.
+	HostKeyPath string
+	// Where each player's stats and the shared high scores are kept
+	DataDir string
+	// The game every session starts with. Stats and scores are filled
+	// in per session, and so is the seed unless one is given.
+	Game Options
+	// Where to log who comes and goes. Nil means don't.
+	Log *log.Logger
+}
+
+// Where the SSH server keeps its files unless told otherwise
+func DefaultSSHDir() string {
+	return filepath.Join(dataDir(), "ssh")
+}
+
+// Serve the game over SSH until something goes wrong
+func RunSSH(opts SSHOptions) error {
+	if opts.Log == nil {
+		opts.Log = log.New(io.Discard, "", 0)
+	}
+	if err := ensureHostKey(opts.HostKeyPath); err != nil {
+		return err
+	}
+	if opts.Game.Theme.Name != "" {
+		ApplyTheme(opts.Game.Theme)
+	}
+	// The colors are for the players' terminals, not this one. Most
+	// can do 256 colors and most are dark.
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+
+	server := &ssh.Server{
+		Addr:    net.JoinHostPort("", strconv.Itoa(opts.Port)),
+		Handler: func(s ssh.Session) { playSession(s, opts) },
+	}
+	// Any key will do. It's only used to keep each player's stats.
+	server.SetOption(ssh.PublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool {
+		return true
+	}))
+	if err := server.SetOption(ssh.HostKeyFile(opts.HostKeyPath)); err != nil {
+		return fmt.Errorf("%s: %w", opts.HostKeyPath, err)
+	}
+	opts.Log.Printf("serving hangman over SSH on port %d", opts.Port)
+	return server.ListenAndServe()
+}
+
+// A player's key as something that can go in a file name
+func fingerprint(key ssh.PublicKey) string {
+	fp := strings.TrimPrefix(gossh.FingerprintSHA256(key), "SHA256:")
+	return strings.NewReplacer("/", "_", "+", "-").Replace(fp)
+}
+
+// Play one game session
+func playSession(s ssh.Session, opts SSHOptions) {
+	_, windows, ok := s.Pty()
+	if !ok {
+		fmt.Fprintln(s, "Hangman needs a terminal. Try ssh -t")
+		s.Exit(1)
+		return
+	}
+	fp := fingerprint(s.PublicKey())
+	opts.Log.Printf("%s (%s) connected from %s", s.User(), fp, s.RemoteAddr())
+
+	game := opts.Game
+	game.StatsPath = filepath.Join(opts.DataDir, "players", fp, "stats.json")
+	game.ScoresPath = filepath.Join(opts.DataDir, "scores.json")
+	game.Remote = true
+	// Everyone gets their own words
+	if game.Seed == 0 {
+		game.Seed = time.Now().UnixNano()
+	}
+
+	// The first window size comes through the channel too
+	p := tea.NewProgram(
+		sessionModel{model: initialModel(game), windows: windows},
+		tea.WithInput(s),
+		tea.WithOutput(s),
+	)
+	// Stop the game if the player hangs up without quitting
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-s.Context().Done():
+			p.Kill()
+		case <-done:
+		}
+	}()
+	err := p.Start()
+	close(done)
+	if err != nil {
+		opts.Log.Printf("%s: %v", fp, err)
+		s.Exit(1)
+		return
+	}
+	opts.Log.Printf("%s (%s) left", s.User(), fp)
+	s.Exit(0)
+}
+
+// The game, plus the session's window size changes
+type sessionModel struct {
+	model
+	windows <-chan ssh.Window
+}
+
+// Wait for the terminal to change size
+func waitForWindow(windows <-chan ssh.Window) tea.Cmd {
+	return func() tea.Msg {
+		w, ok := <-windows
+		if !ok {
+			return nil
+		}
+		return tea.WindowSizeMsg{Width: w.Width, Height: w.Height}
+	}
+}
+
+func (m sessionModel) Init() tea.Cmd {
+	return tea.Batch(m.model.Init(), waitForWindow(m.windows))
+}
+
+func (m sessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.model.Update(msg)
+	m.model = updated.(model)
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		cmd = tea.Batch(cmd, waitForWindow(m.windows))
+	}
+	return m, cmd
+}
+
+// Make a host key if there isn't one yet
+func ensureHostKey(path string) error {
+	_, err := os.Stat(path)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+}
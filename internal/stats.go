@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ******************************************************************
+//
+//	Stats stuff
+//
+// Every finished game is appended to a JSON file so players can see
+// how they're doing over time.
+// ******************************************************************
+
+// Everything worth remembering about one finished game
+type GameRecord struct {
+	Word string `json:"word"`
+	// Every guess, in order
+	Guesses    []string `json:"guesses"`
+	Misses     int      `json:"misses"`
+	MaxMisses  int      `json:"max_misses"`
+	Won        bool     `json:"won"`
+	Difficulty string   `json:"difficulty"`
+	Category   string   `json:"category,omitempty"`
+	// Replays the game with the same options. Zero if there's no seed.
+	Seed    int64     `json:"seed,omitempty"`
+	Started time.Time `json:"started"`
+	// How long the game took, in milliseconds
+	DurationMS int64 `json:"duration_ms"`
+}
+
+func (r GameRecord) Duration() time.Duration {
+	return time.Duration(r.DurationMS) * time.Millisecond
+}
+
+type Stats struct {
+	Games []GameRecord `json:"games"`
+}
+
+// Where stats are kept unless told otherwise
+func DefaultStatsPath() string {
+	return filepath.Join(dataDir(), "stats.json")
+}
+
+// Read the stats file. A missing file is just a player with no games yet.
+func LoadStats(path string) (Stats, error) {
+	var s Stats
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Write the stats file, creating directories as needed
+func SaveStats(path string, s Stats) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// Write a file the game keeps, creating directories as needed.
+// Writes to a temp file of its own first, so a crash can't leave half
+// a file behind and two writers can't swap each other's halves.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Held while a kept file is read, changed and written back, so games
+// running in the same process (like SSH sessions) take turns
+var keptFilesMu sync.Mutex
+
+// Add a game to the stats file. The file is re-read first to pick up
+// games other sessions have added.
+func RecordGame(path string, r GameRecord) error {
+	keptFilesMu.Lock()
+	defer keptFilesMu.Unlock()
+	s, err := LoadStats(path)
+	if err != nil {
+		return err
+	}
+	s.Games = append(s.Games, r)
+	return SaveStats(path, s)
+}
+
+// The numbers shown on the stats screen
+type Summary struct {
+	Played        int
+	Wins          int
+	CurrentStreak int
+	BestStreak    int
+	// Misses averaged over every game
+	AverageMisses float64
+	// How many wins had 0 misses, 1 miss, 2 misses...
+	MissesPerWin []int
+}
+
+func (sum Summary) WinRate() float64 {
+	if sum.Played == 0 {
+		return 0
+	}
+	return float64(sum.Wins) / float64(sum.Played) * 100
+}
+
+func (s Stats) Summarize() Summary {
+	var sum Summary
+	totalMisses := 0
+	streak := 0
+	for _, g := range s.Games {
+		sum.Played++
+		totalMisses += g.Misses
+		if !g.Won {
+			streak = 0
+			continue
+		}
+		sum.Wins++
+		streak++
+		if streak > sum.BestStreak {
+			sum.BestStreak = streak
+		}
+		for len(sum.MissesPerWin) <= g.Misses {
+			sum.MissesPerWin = append(sum.MissesPerWin, 0)
+		}
+		sum.MissesPerWin[g.Misses]++
+	}
+	sum.CurrentStreak = streak
+	if sum.Played > 0 {
+		sum.AverageMisses = float64(totalMisses) / float64(sum.Played)
+	}
+	return sum
+}
+
+// How wide the longest histogram bar gets
+const maxHistogramBar = 30
+
+// Render the summary like Wordle's statistics screen:
+// a row of big numbers and a bar chart of misses per win
+func (sum Summary) View() string {
+	numbers := []struct {
+		value string
+		label string
+	}{
+		{fmt.Sprint(sum.Played), "Played"},
+		{fmt.Sprintf("%.0f", sum.WinRate()), "Win %"},
+		{fmt.Sprint(sum.CurrentStreak), "Current\nStreak"},
+		{fmt.Sprint(sum.BestStreak), "Best\nStreak"},
+		{fmt.Sprintf("%.1f", sum.AverageMisses), "Avg\nMisses"},
+	}
+	var cells []string
+	for _, n := range numbers {
+		cells = append(cells, statNumberStyle.Render(
+			lipgloss.JoinVertical(lipgloss.Center, statValueStyle.Render(n.value), n.label),
+		))
+	}
+	s := menuHeadingStyle.Render("Statistics") + "\n"
+	s += lipgloss.JoinHorizontal(lipgloss.Top, cells...) + "\n\n"
+
+	s += menuHeadingStyle.Render("Misses per win") + "\n"
+	if sum.Wins == 0 {
+		return s + statLabelStyle.Render("No wins yet. Keep at it!")
+	}
+	most := 0
+	for _, n := range sum.MissesPerWin {
+		if n > most {
+			most = n
+		}
+	}
+	var rows []string
+	for misses, n := range sum.MissesPerWin {
+		width := n * maxHistogramBar / most
+		bar := statBarStyle.Render(strings.Repeat(" ", width) + fmt.Sprint(n))
+		if n == 0 {
+			bar = statEmptyBarStyle.Render("0")
+		}
+		rows = append(rows, statLabelStyle.Render(fmt.Sprintf("%2d ", misses))+bar)
+	}
+	return s + strings.Join(rows, "\n")
+}
+
+// Print the stats screen, for `hangman stats`
+func PrintStats(path string) error {
+	s, err := LoadStats(path)
+	if err != nil {
+		return err
+	}
+	fmt.Println(s.Summarize().View())
+	return nil
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// SSH sessions share the scores file, and a player's stats file if
+// they have two sessions open. Nothing written at once is lost.
+func TestConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	statsPath := filepath.Join(dir, "stats.json")
+	scoresPath := filepath.Join(dir, "scores.json")
+
+	const sessions = 50
+	var wg sync.WaitGroup
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := RecordGame(statsPath, GameRecord{Word: fmt.Sprint(i)}); err != nil {
+				t.Error(err)
+			}
+			if _, _, err := AddHighScore(scoresPath, HighScore{Initials: "AAA", Score: 100 + i}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := LoadStats(statsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Games) != sessions {
+		t.Errorf("%d games recorded, want %d", len(s.Games), sessions)
+	}
+	h, err := LoadHighScores(scoresPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Scores) != MaxHighScores || h.Scores[0].Score != 100+sessions-1 {
+		t.Errorf("high scores = %+v", h.Scores)
+	}
+
+	// No temp files are left lying around
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("files left behind: %v", names)
+	}
+}
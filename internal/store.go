@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/braheezy/hangman/game"
+)
+
+// ******************************************************************
+//
+//	Game store stuff
+//
+// Where the API keeps its games between requests. Games are kept as
+// plain data, the word and the guesses, and rebuilt by replaying the
+// guesses. That way any store that can hold JSON can hold games.
+// ******************************************************************
+
+// No game with that ID. Maybe it was never there, maybe it expired.
+var ErrGameNotFound = errors.New("game not found")
+
+// A game as the API keeps it
+type StoredGame struct {
+	ID         string    `json:"id"`
+	Word       string    `json:"word"`
+	MaxMisses  int       `json:"max_misses"`
+	Guesses    []string  `json:"guesses"`
+	Difficulty string    `json:"difficulty"`
+	Category   string    `json:"category,omitempty"`
+	Seed       int64     `json:"seed"`
+	Created    time.Time `json:"created"`
+	// The last time anyone guessed
+	Updated time.Time `json:"updated"`
+}
+
+// Rebuild the game by playing every guess again
+func (s StoredGame) Game() (*game.Game, error) {
+	g := game.New(s.Word, s.MaxMisses)
+	for _, guess := range s.Guesses {
+		var err error
+		if len([]rune(guess)) > 1 {
+			_, err = g.Solve(guess)
+		} else {
+			_, err = g.Guess(guess)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Somewhere to keep games. Swap in another one, like a file-backed
+// store, to keep games around across restarts.
+type GameStore interface {
+	// The game with this ID, or ErrGameNotFound
+	Get(id string) (StoredGame, error)
+	// Add a game, or replace the one with the same ID
+	Put(g StoredGame) error
+}
+
+// Keeps games in memory and forgets them once nobody has touched them
+// for a while
+type MemoryStore struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	games map[string]StoredGame
+}
+
+// A store that forgets games left alone for ttl. Zero means never.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:   ttl,
+		games: map[string]StoredGame{},
+	}
+}
+
+func (s *MemoryStore) Get(id string) (StoredGame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.games[id]
+	if !ok || s.expired(g, time.Now()) {
+		return StoredGame{}, ErrGameNotFound
+	}
+	return g, nil
+}
+
+func (s *MemoryStore) Put(g StoredGame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games[g.ID] = g
+	return nil
+}
+
+// Forget every game that's expired. Returns how many went.
+func (s *MemoryStore) Evict(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	evicted := 0
+	for id, g := range s.games {
+		if s.expired(g, now) {
+			delete(s.games, id)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+func (s *MemoryStore) expired(g StoredGame, now time.Time) bool {
+	return s.ttl > 0 && now.Sub(g.Updated) > s.ttl
+}
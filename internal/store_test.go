@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTTL(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore(time.Minute)
+	s.Put(StoredGame{ID: "old", Word: "banana", MaxMisses: 8, Updated: now.Add(-2 * time.Minute)})
+	s.Put(StoredGame{ID: "new", Word: "banana", MaxMisses: 8, Updated: now})
+
+	// Expired games are gone before they're evicted
+	if _, err := s.Get("old"); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("expired game: err = %v, want %v", err, ErrGameNotFound)
+	}
+	if _, err := s.Get("new"); err != nil {
+		t.Errorf("fresh game: %v", err)
+	}
+	if n := s.Evict(now); n != 1 {
+		t.Errorf("evicted %d games, want 1", n)
+	}
+	if n := s.Evict(now.Add(2 * time.Minute)); n != 1 {
+		t.Errorf("evicted %d games later on, want 1", n)
+	}
+
+	// Zero keeps games forever
+	s = NewMemoryStore(0)
+	s.Put(StoredGame{ID: "old", Word: "banana", MaxMisses: 8, Updated: now.Add(-24 * time.Hour)})
+	if _, err := s.Get("old"); err != nil || s.Evict(now) != 0 {
+		t.Errorf("a store with no TTL forgot a game: %v", err)
+	}
+}
+
+func TestStoredGame(t *testing.T) {
+	g, err := StoredGame{Word: "banana", MaxMisses: 8, Guesses: []string{"A", "Z", "BANANE"}}.Game()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Pattern() != "_A_A_A" || g.Misses() != 3 {
+		t.Errorf("rebuilt game = %+v", g.State())
+	}
+	if _, err := (StoredGame{Word: "banana", MaxMisses: 8, Guesses: []string{"A", "A"}}).Game(); err == nil {
+		t.Error("a game with a repeated guess rebuilt fine")
+	}
+}
@@ -0,0 +1,236 @@
+package internal
+
+import "github.com/charmbracelet/lipgloss"
+
+// ******************************************************************
+//
+//		Styles
+//	Every lipgloss style in the app. They're built from the colors in
+//	colors.go, so they get rebuilt whenever the theme changes.
+//
+// ******************************************************************
+var (
+	titleStyle            lipgloss.Style
+	footerStyle           lipgloss.Style
+	difficultyStyle       lipgloss.Style
+	noticeStyle           lipgloss.Style
+	loseNoticeStyle       lipgloss.Style
+	winNoticeStyle        lipgloss.Style
+	tallyStyle            lipgloss.Style
+	boardTileStyle        lipgloss.Style
+	boardGapStyle         lipgloss.Style
+	boardPunctuationStyle lipgloss.Style
+	letterOffStyle        lipgloss.Style
+	letterOnStyle         lipgloss.Style
+	letterHoverStyle      lipgloss.Style
+	baseGraphicStyle      lipgloss.Style
+	graphicStyle          lipgloss.Style
+	flashWrongStyle       lipgloss.Style
+	flashCorrectStyle     lipgloss.Style
+	menuHeadingStyle      lipgloss.Style
+	menuItemStyle         lipgloss.Style
+	menuSelectedStyle     lipgloss.Style
+	menuDescriptionStyle  lipgloss.Style
+	statNumberStyle       lipgloss.Style
+	statValueStyle        lipgloss.Style
+	statLabelStyle        lipgloss.Style
+	statBarStyle          lipgloss.Style
+	statEmptyBarStyle     lipgloss.Style
+	hintStyle             lipgloss.Style
+	setupLabelStyle       lipgloss.Style
+	shareStyle            lipgloss.Style
+	timerStyle            lipgloss.Style
+	timerLowStyle         lipgloss.Style
+	scoreStyle            lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+}
+
+// (Re)create every style from the current colors
+func buildStyles() {
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Align(lipgloss.Center).
+		Foreground(textColor).
+		Background(primaryColor).
+		PaddingLeft(2).
+		PaddingRight(2).
+		MarginBottom(1)
+
+	footerStyle = lipgloss.NewStyle().
+		Foreground(primaryColor).
+		Underline(true)
+
+	// A little badge in front of the footer naming the difficulty
+	difficultyStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(textColor).
+		Background(secondaryColor).
+		PaddingLeft(1).
+		PaddingRight(1).
+		MarginRight(1)
+
+	noticeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Italic(true).
+		Foreground(primaryColor)
+
+	loseNoticeStyle = lipgloss.NewStyle().
+		Inherit(noticeStyle).
+		Foreground(failColor)
+
+	winNoticeStyle = lipgloss.NewStyle().
+		Inherit(noticeStyle).
+		Foreground(successColor)
+
+	// The running score of the session
+	tallyStyle = lipgloss.NewStyle().
+		Foreground(secondaryColor)
+
+	boardTileStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(secondaryColor).
+		Background(strongColor).
+		Width(5).
+		Align(lipgloss.Center)
+
+	// Between the words of a phrase
+	boardGapStyle = lipgloss.NewStyle().
+		Width(1)
+
+	// Hyphens, apostrophes and digits in a phrase. Nothing to guess, so no tile.
+	boardPunctuationStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(secondaryColor)
+
+	letterOffStyle = lipgloss.NewStyle().
+		Foreground(textColor).
+		Background(primaryColor).
+		Width(3).
+		Align(lipgloss.Center)
+
+	letterOnStyle = lipgloss.NewStyle().
+		Foreground(primaryColor).
+		Background(tertiaryColor).
+		Width(3).
+		Align(lipgloss.Center).
+		Bold(true)
+
+	// A letter under the mouse
+	letterHoverStyle = lipgloss.NewStyle().
+		Foreground(textColor).
+		Background(secondaryColor).
+		Width(3).
+		Align(lipgloss.Center).
+		Bold(true)
+
+	baseGraphicStyle = lipgloss.NewStyle().
+		Bold(true).
+		Border(lipgloss.RoundedBorder()).
+		BorderBackground(backgroundColor).
+		Background(backgroundColor)
+
+	graphicStyle = lipgloss.NewStyle().
+		Inherit(baseGraphicStyle).
+		Padding(1, 3, 1, 3).
+		Foreground(primaryColor).
+		BorderForeground(primaryColor)
+
+	flashWrongStyle = lipgloss.NewStyle().
+		Inherit(baseGraphicStyle).
+		Padding(1, 3, 1, 3).
+		Foreground(failColor).
+		BorderForeground(failColor)
+
+	flashCorrectStyle = lipgloss.NewStyle().
+		Inherit(baseGraphicStyle).
+		Padding(1, 3, 1, 3).
+		Foreground(successColor).
+		BorderForeground(successColor)
+
+	menuHeadingStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(textColor).
+		Background(primaryColor).
+		PaddingLeft(1).
+		PaddingRight(1).
+		MarginBottom(1)
+
+	menuItemStyle = lipgloss.NewStyle().
+		Foreground(primaryColor).
+		PaddingLeft(2)
+
+	menuSelectedStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(secondaryColor).
+		PaddingLeft(2)
+
+	menuDescriptionStyle = lipgloss.NewStyle().
+		Italic(true).
+		Faint(true).
+		Foreground(primaryColor)
+
+	// Each big number on the stats screen
+	statNumberStyle = lipgloss.NewStyle().
+		Foreground(primaryColor).
+		Align(lipgloss.Center).
+		Width(9)
+
+	statValueStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(secondaryColor)
+
+	statLabelStyle = lipgloss.NewStyle().
+		Foreground(primaryColor)
+
+	// The bars of the misses per win histogram
+	statBarStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(textColor).
+		Background(successColor).
+		PaddingRight(1)
+
+	statEmptyBarStyle = lipgloss.NewStyle().
+		Foreground(textColor).
+		Background(tertiaryColor).
+		PaddingLeft(1).
+		PaddingRight(1)
+
+	// Clues and categories shown above the board
+	hintStyle = lipgloss.NewStyle().
+		Italic(true).
+		Foreground(secondaryColor)
+
+	// The labels next to the two player setup inputs
+	setupLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Width(8)
+
+	// The daily puzzle summary
+	shareStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(textColor)
+
+	// The countdown next to the hangman
+	timerStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(strongColor).
+		PaddingLeft(2)
+
+	// The countdown when time is nearly up
+	timerLowStyle = lipgloss.NewStyle().
+		Inherit(timerStyle).
+		Foreground(failColor)
+
+	// The live score badge in the footer
+	scoreStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(textColor).
+		Background(tertiaryColor).
+		PaddingLeft(1).
+		PaddingRight(1).
+		MarginRight(1)
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// ******************************************************************
+//
+//	Theme stuff
+//
+// A theme names a color for each role in colors.go, once for light
+// terminals and once for dark ones. Themes are built in or read from
+// YAML, TOML or JSON files in $XDG_CONFIG_HOME/hangman/themes/
+// ******************************************************************
+
+// A color for every role. Empty roles keep the default color.
+type ThemeColors struct {
+	Primary    string `json:"primary" yaml:"primary" toml:"primary"`
+	Secondary  string `json:"secondary" yaml:"secondary" toml:"secondary"`
+	Tertiary   string `json:"tertiary" yaml:"tertiary" toml:"tertiary"`
+	Strong     string `json:"strong" yaml:"strong" toml:"strong"`
+	Success    string `json:"success" yaml:"success" toml:"success"`
+	Fail       string `json:"fail" yaml:"fail" toml:"fail"`
+	Text       string `json:"text" yaml:"text" toml:"text"`
+	Background string `json:"background" yaml:"background" toml:"background"`
+}
+
+type Theme struct {
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// Used when the terminal has a light background
+	Light ThemeColors `json:"light" yaml:"light" toml:"light"`
+	// Used when the terminal has a dark background
+	Dark ThemeColors `json:"dark" yaml:"dark" toml:"dark"`
+}
+
+// The name of the theme used when none is picked
+const DefaultThemeName = "catppuccin"
+
+// The colors hangman has always had: Latte on light terminals and a
+// Macchiato/Mocha mix on dark ones
+var defaultTheme = Theme{
+	Name:  DefaultThemeName,
+	Light: themeColorsFrom(lightOf),
+	Dark:  themeColorsFrom(darkOf),
+}
+
+func lightOf(c lipgloss.AdaptiveColor) string { return c.Light }
+func darkOf(c lipgloss.AdaptiveColor) string  { return c.Dark }
+
+// Read one side of the current colors into ThemeColors
+func themeColorsFrom(side func(lipgloss.AdaptiveColor) string) ThemeColors {
+	return ThemeColors{
+		Primary:    side(primaryColor),
+		Secondary:  side(secondaryColor),
+		Tertiary:   side(tertiaryColor),
+		Strong:     side(strongColor),
+		Success:    side(successColor),
+		Fail:       side(failColor),
+		Text:       side(textColor),
+		Background: side(backgroundColor),
+	}
+}
+
+// Map a single Catppuccin flavor onto the roles. The flavor is used no
+// matter what the terminal background is.
+func flavorTheme(name string, palette map[string]string, light bool) Theme {
+	colors := ThemeColors{
+		Primary:    palette["Mauve"],
+		Secondary:  palette["Pink"],
+		Tertiary:   palette["Surface2"],
+		Strong:     palette["Surface1"],
+		Success:    palette["Green"],
+		Fail:       palette["Red"],
+		Text:       palette["Base"],
+		Background: palette["Base"],
+	}
+	if light {
+		colors.Primary = palette["Lavender"]
+		colors.Secondary = palette["Teal"]
+		colors.Strong = palette["Crust"]
+		colors.Text = palette["Text"]
+	}
+	return Theme{Name: name, Light: colors, Dark: colors}
+}
+
+var builtinThemes = []Theme{
+	defaultTheme,
+	flavorTheme("latte", LatteColors, true),
+	flavorTheme("frappe", FrappeColors, false),
+	flavorTheme("macchiato", MacchiatoColors, false),
+	flavorTheme("mocha", MochaColors, false),
+}
+
+// Where theme files are looked for
+func themesDir() string {
+	return filepath.Join(configDir(), "themes")
+}
+
+var themeExtensions = []string{".yaml", ".yml", ".toml", ".json"}
+
+// Find a theme by name. Built-in themes win, then files in the themes
+// directory. A path to a theme file works too.
+func LoadTheme(name string) (Theme, error) {
+	// People will type it properly
+	key := strings.ToLower(strings.ReplaceAll(name, "é", "e"))
+	for _, t := range builtinThemes {
+		if t.Name == key {
+			return t, nil
+		}
+	}
+
+	if strings.ContainsRune(name, os.PathSeparator) || filepath.Ext(name) != "" {
+		return readThemeFile(name)
+	}
+	for _, ext := range themeExtensions {
+		path := filepath.Join(themesDir(), name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return readThemeFile(path)
+		}
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+}
+
+// Every theme that can be picked: built-ins and files in the themes directory
+func ThemeNames() []string {
+	var names []string
+	for _, t := range builtinThemes {
+		names = append(names, t.Name)
+	}
+	entries, _ := os.ReadDir(themesDir())
+	var custom []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		for _, known := range themeExtensions {
+			if ext == known {
+				custom = append(custom, strings.TrimSuffix(e.Name(), ext))
+			}
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// Decode a theme file based on its extension and check the colors
+func readThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var t Theme
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &t)
+	case ".toml":
+		err = toml.Unmarshal(data, &t)
+	case ".json":
+		err = json.Unmarshal(data, &t)
+	default:
+		return Theme{}, fmt.Errorf("theme %s: unsupported file type, use one of %s", path, strings.Join(themeExtensions, ", "))
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := t.Validate(); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return t, nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Each role name paired with its color, in a fixed order
+func (c ThemeColors) roles() [][2]string {
+	return [][2]string{
+		{"primary", c.Primary},
+		{"secondary", c.Secondary},
+		{"tertiary", c.Tertiary},
+		{"strong", c.Strong},
+		{"success", c.Success},
+		{"fail", c.Fail},
+		{"text", c.Text},
+		{"background", c.Background},
+	}
+}
+
+// Make sure every color given is a hex code
+func (t Theme) Validate() error {
+	var errs []string
+	for _, side := range []struct {
+		name   string
+		colors ThemeColors
+	}{{"light", t.Light}, {"dark", t.Dark}} {
+		for _, role := range side.colors.roles() {
+			if role[1] != "" && !hexColor.MatchString(role[1]) {
+				errs = append(errs, fmt.Sprintf("%s.%s: %q is not a hex color like \"#cba6f7\"", side.name, role[0], role[1]))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// The first color that is set
+func pick(colors ...string) string {
+	for _, c := range colors {
+		if c != "" {
+			return c
+		}
+	}
+	return ""
+}
+
+// Pick the colors for a role, falling back to the other side of the
+// theme and then to the default theme
+func adaptive(light, dark, defaultLight, defaultDark string) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{
+		Light: pick(light, dark, defaultLight),
+		Dark:  pick(dark, light, defaultDark),
+	}
+}
+
+// Swap the app colors for the theme's and rebuild the styles
+func ApplyTheme(t Theme) {
+	l, d := t.Light, t.Dark
+	dl, dd := defaultTheme.Light, defaultTheme.Dark
+	primaryColor = adaptive(l.Primary, d.Primary, dl.Primary, dd.Primary)
+	secondaryColor = adaptive(l.Secondary, d.Secondary, dl.Secondary, dd.Secondary)
+	tertiaryColor = adaptive(l.Tertiary, d.Tertiary, dl.Tertiary, dd.Tertiary)
+	strongColor = adaptive(l.Strong, d.Strong, dl.Strong, dd.Strong)
+	successColor = adaptive(l.Success, d.Success, dl.Success, dd.Success)
+	failColor = adaptive(l.Fail, d.Fail, dl.Fail, dd.Fail)
+	textColor = adaptive(l.Text, d.Text, dl.Text, dd.Text)
+	backgroundColor = adaptive(l.Background, d.Background, dl.Background, dd.Background)
+	buildStyles()
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ******************************************************************
+//
+//	Timer stuff
+//
+// An optional countdown shown next to the hangman. Either each guess
+// is on the clock, or the whole session is a race to solve as many
+// words as possible.
+// ******************************************************************
+type TimerMode int
+
+const (
+	// No clock at all
+	NoTimer TimerMode = iota
+	// Every guess has to be made in time. Running out costs a life.
+	GuessTimer
+	// Solve as many words as possible before the clock runs out
+	RaceTimer
+)
+
+// The names the --timer flag understands, in TimerMode order
+var timerModeNames = []string{"off", "guess", "race"}
+
+func (t TimerMode) String() string {
+	return timerModeNames[t]
+}
+
+// Look up a timer mode by name
+func ParseTimerMode(name string) (TimerMode, error) {
+	for i, n := range timerModeNames {
+		if strings.EqualFold(name, n) {
+			return TimerMode(i), nil
+		}
+	}
+	return NoTimer, fmt.Errorf("unknown timer %q (choose from %s)", name, strings.Join(timerModeNames, ", "))
+}
+
+// How long the clock runs when no time is given
+func (t TimerMode) DefaultLimit() time.Duration {
+	if t == RaceTimer {
+		return 3 * time.Minute
+	}
+	return 15 * time.Second
+}
+
+// How often the clock ticks
+const tickInterval = time.Second
+
+// Sent every tick while there's a timer
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(tickInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// Time counting down. It only counts while it's told to, so menus
+// and finished games don't eat into it.
+type Countdown struct {
+	mode  TimerMode
+	limit time.Duration
+	// How much time is left
+	left time.Duration
+	// When the time left was last worked out
+	last time.Time
+}
+
+func NewCountdown(mode TimerMode, limit time.Duration) Countdown {
+	if limit <= 0 {
+		limit = mode.DefaultLimit()
+	}
+	return Countdown{
+		mode:  mode,
+		limit: limit,
+		left:  limit,
+		last:  time.Now(),
+	}
+}
+
+// Is there a clock at all?
+func (c Countdown) On() bool {
+	return c.mode != NoTimer
+}
+
+// Has the time run out?
+func (c Countdown) Expired() bool {
+	return c.left <= 0
+}
+
+// Put all the time back on the clock
+func (c *Countdown) Reset(now time.Time) {
+	c.left = c.limit
+	c.last = now
+}
+
+// Take the time since the last update off the clock, if it's counting
+func (c *Countdown) Update(now time.Time, counting bool) {
+	if counting {
+		c.left -= now.Sub(c.last)
+	}
+	c.last = now
+}
+
+// The time left, like 0:09. It turns red when it's nearly out.
+func (c Countdown) View() string {
+	left := c.left
+	if left < 0 {
+		left = 0
+	}
+	// Round up so the clock shows 0:00 only once time is up
+	secs := int((left + time.Second - 1) / time.Second)
+	style := timerStyle
+	if left <= c.limit/4 || left <= 5*time.Second {
+		style = timerLowStyle
+	}
+	return style.Render(fmt.Sprintf("⏱ %d:%02d", secs/60, secs%60))
+}
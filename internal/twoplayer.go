@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/exp/slices"
+)
+
+// ******************************************************************
+//
+//		Two player stuff
+//	Player one secretly types a word (and maybe a clue), then player
+//	two guesses it on the same terminal.
+//
+// ******************************************************************
+type wordSetup struct {
+	// The secret word. Masked so player two can't peek.
+	word textinput.Model
+	// An optional clue for player two
+	clue textinput.Model
+	// Problems with what player one typed
+	notice PrettyString
+}
+
+// The longest word (or phrase) player one can enter
+const maxSetupWordLength = 48
+
+func newWordSetup() wordSetup {
+	word := textinput.New()
+	word.Placeholder = "The secret word or phrase"
+	word.CharLimit = maxSetupWordLength
+	word.EchoMode = textinput.EchoPassword
+	word.EchoCharacter = '•'
+	word.Focus()
+
+	clue := textinput.New()
+	clue.Placeholder = "A clue (optional)"
+	clue.CharLimit = 64
+
+	for _, ti := range []*textinput.Model{&word, &clue} {
+		ti.Prompt = "─> "
+		ti.PromptStyle = lipgloss.NewStyle().
+			Foreground(secondaryColor)
+		ti.PlaceholderStyle = lipgloss.NewStyle().
+			Italic(true).
+			Faint(true).
+			Foreground(secondaryColor)
+	}
+
+	return wordSetup{
+		word:   word,
+		clue:   clue,
+		notice: NewNotice(),
+	}
+}
+
+// Move focus between the word and the clue
+func (setup *wordSetup) toggleFocus() {
+	if setup.word.Focused() {
+		setup.word.Blur()
+		setup.clue.Focus()
+	} else {
+		setup.clue.Blur()
+		setup.word.Focus()
+	}
+}
+
+func (setup wordSetup) View(title PrettyString) string {
+	s := lipgloss.JoinVertical(
+		lipgloss.Left,
+		title.View(),
+		noticeStyle.Render("Player one: enter a word or phrase for player two to guess."),
+		noticeStyle.Render("No peeking, player two!"),
+		"",
+		setupLabelStyle.Render("Word")+setup.word.View(),
+		setupLabelStyle.Render("Clue")+setup.clue.View(),
+	)
+	s += "\n\n"
+	if setup.notice.text != "" {
+		s += setup.notice.View()
+	}
+	s += "\n"
+	s += footerStyle.Render("Tab to switch fields, Enter to start, ESC or Ctrl+C to quit.")
+	return s
+}
+
+// Make sure player one's word can be played.
+// Returns the normalized word.
+func checkSetupWord(m *model, word string) (string, error) {
+	word = strings.ToUpper(strings.Join(strings.Fields(word), " "))
+	if !isPuzzle(word) {
+		return "", fmt.Errorf("the word can only have letters, digits, spaces and %s", phrasePunctuation)
+	}
+	if countLetters(word) < 2 {
+		return "", fmt.Errorf("the word needs at least two letters")
+	}
+	if m.checkWords && !slices.Contains(m.wordList, word) {
+		return "", fmt.Errorf("%s isn't in the word list", word)
+	}
+	return word, nil
+}
+
+// Handle typing on the setup screen. Enter on the word moves to the clue,
+// Enter on the clue starts the game.
+func handleSetup(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, tea.Quit
+		case "tab", "shift+tab", "up", "down":
+			m.setup.toggleFocus()
+			return m, nil
+		case "enter":
+			word, err := checkSetupWord(&m, m.setup.word.Value())
+			if err != nil {
+				m.setup.notice.text = err.Error()
+				m.setup.notice.style = loseNoticeStyle
+				if !m.setup.word.Focused() {
+					m.setup.toggleFocus()
+				}
+				return m, nil
+			}
+			if m.setup.word.Focused() {
+				m.setup.notice.text = ""
+				m.setup.toggleFocus()
+				return m, nil
+			}
+			hint := ""
+			if clue := strings.TrimSpace(m.setup.clue.Value()); clue != "" {
+				hint = "Clue: " + clue
+			}
+			// Wipe the screen so nothing of the word lingers for player two
+			m.clearScreen()
+			startGame(&m, word, hint)
+			return m, textinput.Blink
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	if m.setup.word.Focused() {
+		m.setup.word, cmd = m.setup.word.Update(msg)
+	} else {
+		m.setup.clue, cmd = m.setup.clue.Update(msg)
+	}
+	return m, cmd
+}
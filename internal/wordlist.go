@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ******************************************************************
+//
+//	Word list stuff
+//
+// Word lists are plain text: one word or phrase per line. Blank lines
+// and lines starting with # are skipped. Words are trimmed, upper cased
+// and deduplicated. Phrases may also have spaces, digits and a little
+// punctuation. Anything else is rejected.
+// ******************************************************************
+
+// How many bad entries to report before giving up on listing them
+const maxReportedWordErrors = 5
+
+// Read a word list. name is used in error messages.
+func ParseWords(r io.Reader, name string) ([]string, error) {
+	var words []string
+	var problems []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Squash runs of spaces in phrases
+		word := strings.ToUpper(strings.Join(strings.Fields(line), " "))
+		if !isPuzzle(word) {
+			problems = append(problems, fmt.Sprintf("%s:%d: %q needs letters and can only have spaces, digits and %s besides", name, lineNum, line, phrasePunctuation))
+			continue
+		}
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(problems) > 0 {
+		return nil, wordErrors(problems)
+	}
+	return words, nil
+}
+
+// What phrases can have besides letters, digits and spaces
+const phrasePunctuation = "-'’.,!?&:"
+
+// Can this be played? It needs at least one letter to guess, and
+// anything else has to be something the board can show as-is.
+func isPuzzle(s string) bool {
+	letters := 0
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r), r == ' ', strings.ContainsRune(phrasePunctuation, r):
+		default:
+			return false
+		}
+	}
+	return letters > 0
+}
+
+// How many letters there are to guess
+func countLetters(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// Squash a pile of problems into one readable error
+func wordErrors(problems []string) error {
+	shown := problems
+	if len(shown) > maxReportedWordErrors {
+		shown = shown[:maxReportedWordErrors]
+	}
+	msg := strings.Join(shown, "\n")
+	if extra := len(problems) - len(shown); extra > 0 {
+		msg += fmt.Sprintf("\n...and %d more", extra)
+	}
+	return fmt.Errorf("invalid word list entries:\n%s", msg)
+}
+
+// Load a word list file, or every file in a directory.
+// Words are deduplicated across all the files.
+func LoadWordList(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = nil
+		for _, e := range entries {
+			// Skip subdirectories and dotfiles like .DS_Store
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+		sort.Strings(files)
+	}
+
+	var words []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		fileWords, err := readWordFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range fileWords {
+			if !seen[w] {
+				seen[w] = true
+				words = append(words, w)
+			}
+		}
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%s: no words found", path)
+	}
+	return words, nil
+}
+
+func readWordFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseWords(f, path)
+}
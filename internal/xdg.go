@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ******************************************************************
+//
+//	Where files live
+//
+// Follows the XDG Base Directory spec, falling back to the usual
+// dot directories in $HOME when the variables aren't set.
+// ******************************************************************
+func xdgDir(env string, fallback ...string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return filepath.Join(dir, "hangman")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(append(append([]string{home}, fallback...), "hangman")...)
+}
+
+// Settings the player writes: themes, word packs
+func configDir() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// Things the game writes: stats, scores
+func dataDir() string {
+	return xdgDir("XDG_DATA_HOME", ".local", "share")
+}
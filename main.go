@@ -1,16 +1,487 @@
 package main
 
 import (
-	"math/rand"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/braheezy/hangman/game"
 	"github.com/braheezy/hangman/internal"
+	"github.com/mattn/go-isatty"
 )
 
-func init() {
-	rand.Seed(time.Now().UTC().UnixNano())
+func main() {
+	// Subcommands come first, everything else is flags for the game
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "stats":
+			os.Exit(stats(os.Args[2:]))
+		case "solve":
+			os.Exit(solve(os.Args[2:]))
+		case "bench":
+			os.Exit(bench(os.Args[2:]))
+		case "serve":
+			os.Exit(serve(os.Args[2:]))
+		case "join":
+			os.Exit(join(os.Args[2:]))
+		case "ssh":
+			os.Exit(sshServe(os.Args[2:]))
+		case "api":
+			os.Exit(api(os.Args[2:]))
+		case "replay":
+			os.Exit(replay(os.Args[2:]))
+		}
+	}
+	os.Exit(play(os.Args[1:]))
 }
 
-func main() {
-	internal.Run()
+// Use the seed given, or make one up from the clock
+func pickSeed(seed int64) int64 {
+	if seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// Report a problem and pick an exit code for it
+func fail(err error) int {
+	fmt.Fprintln(os.Stderr, err)
+	return 2
+}
+
+// Start the TUI game
+func play(args []string) int {
+	flags := flag.NewFlagSet("hangman", flag.ExitOnError)
+	difficulty := flags.String("difficulty", game.Medium.Name, "how hard the game is: easy, medium, hard or expert")
+	theme := flags.String("theme", internal.DefaultThemeName, "color theme: a built-in Catppuccin flavor or a file in the themes directory")
+	wordlist := flags.String("wordlist", "", "play words from this file, or every file in this directory, instead of the dictionary")
+	category := flags.String("category", "", "play words from a word pack, like animals or movies")
+	phrases := flags.Bool("phrases", false, "guess common sayings instead of single words (same as --category phrases)")
+	statsFile := flags.String("stats-file", internal.DefaultStatsPath(), "where to record finished games")
+	scoresFile := flags.String("scores-file", internal.DefaultScoresPath(), "where to keep the high scores")
+	twoPlayer := flags.Bool("two-player", false, "player one secretly enters each word for player two to guess")
+	checkWords := flags.Bool("check-words", false, "in two player mode, only accept words from the dictionary or word list")
+	evil := flags.Bool("evil", false, "evil mode: the game dodges your guesses by changing the word")
+	seed := flags.Int64("seed", 0, "seed for picking words, 0 for a random one. Share it (with the same options) to play the same game")
+	solvePenalty := flags.Int("solve-penalty", game.DefaultSolvePenalty, "how many misses a wrong attempt to solve the whole word costs")
+	daily := flags.Bool("daily", false, "play today's puzzle: the same word for everyone, once a day")
+	timer := flags.String("timer", "off", "put the game on the clock: off, guess (each guess is timed, running out costs a life) or race (solve as many words as you can)")
+	timeLimit := flags.Duration("time", 0, "how long the timer runs, like 10s or 2m. 0 for 15s per guess or 3m per race")
+	plain := flags.Bool("plain", false, "no TUI: print the board line by line and read guesses from stdin. On when stdout isn't a terminal")
+	practice := flags.Bool("practice", false, "practice mode: Ctrl+Z (or U once the game is over) takes back a guess, and games aren't kept in stats or scores")
+	replayDir := flags.String("replay-dir", "", "save a replay of every game in this directory, to watch with hangman replay")
+	protocol := flags.String("protocol", "", "talk to a program instead of a person: jsonl reads {\"guess\":\"E\"} lines from stdin and writes a JSON event per line to stdout")
+	flags.Parse(args)
+	// Nobody's looking at a TUI down a pipe
+	if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		*plain = true
+	}
+
+	d, err := game.ParseDifficulty(*difficulty)
+	if err != nil {
+		return fail(err)
+	}
+	timerMode, err := internal.ParseTimerMode(*timer)
+	if err != nil {
+		return fail(err)
+	}
+	if *timeLimit < 0 {
+		return fail(fmt.Errorf("--time can't be negative"))
+	}
+	t, err := internal.LoadTheme(*theme)
+	if err != nil {
+		return fail(err)
+	}
+
+	packs, err := internal.LoadPacks()
+	if err != nil {
+		return fail(err)
+	}
+	if *phrases && *category != "" {
+		return fail(fmt.Errorf("--phrases is short for --category phrases, so pick one or the other"))
+	}
+	if *phrases {
+		*category = "phrases"
+	}
+	if *category != "" && *wordlist != "" {
+		return fail(fmt.Errorf("pick either --category or --wordlist, not both"))
+	}
+	if *daily && (*category != "" || *wordlist != "" || *twoPlayer || *evil) {
+		return fail(fmt.Errorf("--daily is the same puzzle for everyone, so it can't be mixed with --category, --wordlist, --two-player or --evil"))
+	}
+	if *daily && timerMode == internal.RaceTimer {
+		return fail(fmt.Errorf("--daily is only one word, so there's nothing to race"))
+	}
+	if *practice && (*daily || timerMode != internal.NoTimer) {
+		return fail(fmt.Errorf("--practice can't be mixed with --daily or --timer"))
+	}
+	if *protocol != "" && *protocol != "jsonl" {
+		return fail(fmt.Errorf("unknown protocol %q, the only one is jsonl", *protocol))
+	}
+	if *protocol != "" && (*twoPlayer || timerMode != internal.NoTimer || *practice) {
+		return fail(fmt.Errorf("--protocol can't be mixed with --two-player, --timer or --practice"))
+	}
+	if *plain && *protocol == "" && (*twoPlayer || timerMode != internal.NoTimer || *practice) {
+		return fail(fmt.Errorf("--plain can't be mixed with --two-player, --timer or --practice"))
+	}
+
+	dailyNumber := 0
+	if *daily {
+		dailyNumber = internal.DailyNumber(time.Now())
+		state, err := internal.LoadDaily(internal.DefaultDailyPath())
+		if err != nil {
+			return fail(err)
+		}
+		if state.Number == dailyNumber && *protocol == "jsonl" {
+			if err := internal.WriteAlreadyPlayed(os.Stdout, state); err != nil {
+				return fail(err)
+			}
+			return 0
+		}
+		if state.Number == dailyNumber {
+			if state.Summary == "" {
+				fmt.Printf("You've already started today's puzzle and quit part way through.\n\nCome back tomorrow for #%d!\n", dailyNumber+1)
+			} else {
+				fmt.Printf("You've already played today's puzzle:\n\n%s\n\nCome back tomorrow for #%d!\n", state.Summary, dailyNumber+1)
+			}
+			return 0
+		}
+		d = internal.DailyDifficulty
+	}
+
+	var words []string
+	var pack internal.WordPack
+	if *category != "" {
+		pack, err = internal.FindPack(packs, *category)
+		if err != nil {
+			return fail(err)
+		}
+		words = pack.Words
+	}
+	if *wordlist != "" {
+		words, err = internal.LoadWordList(*wordlist)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	opts := internal.Options{
+		Difficulty: d,
+		Words:      words,
+		Category:   pack.Name,
+		Packs:      packs,
+		// Let the player pick a category unless they already picked words
+		StartMenu:    *category == "" && *wordlist == "" && !*twoPlayer && !*daily,
+		Theme:        t,
+		StatsPath:    *statsFile,
+		TwoPlayer:    *twoPlayer,
+		CheckWords:   *checkWords,
+		SolvePenalty: *solvePenalty,
+		Evil:         *evil,
+		Seed:         pickSeed(*seed),
+		Daily:        dailyNumber,
+		DailyPath:    internal.DefaultDailyPath(),
+		Timer:        timerMode,
+		TimeLimit:    *timeLimit,
+		ScoresPath:   *scoresFile,
+		ReplayDir:    *replayDir,
+		Practice:     *practice,
+	}
+	if *protocol == "jsonl" {
+		if err := internal.RunJSONL(os.Stdin, os.Stdout, opts); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	if *plain {
+		if err := internal.RunPlain(os.Stdin, os.Stdout, opts); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	internal.Run(opts)
+	return 0
+}
+
+// Print stats from every game played
+func stats(args []string) int {
+	flags := flag.NewFlagSet("hangman stats", flag.ExitOnError)
+	statsFile := flags.String("stats-file", internal.DefaultStatsPath(), "where finished games are recorded")
+	flags.Parse(args)
+
+	if err := internal.PrintStats(*statsFile); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Rank the best next letters for a board
+func solve(args []string) int {
+	flags := flag.NewFlagSet("hangman solve", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: hangman solve PATTERN [flags]\n\nPATTERN is the board with _ for blanks, like _A__MA_")
+		flags.PrintDefaults()
+	}
+	wrong := flags.String("wrong", "", "letters guessed that aren't in the word, like ETS")
+	wordlist := flags.String("wordlist", "", "solve against this word list instead of the dictionary")
+	top := flags.Int("top", 10, "how many letters to list, 0 for all")
+	flags.Parse(args)
+	// Flags can come after the pattern too
+	pattern := flags.Arg(0)
+	if flags.NArg() > 1 {
+		flags.Parse(flags.Args()[1:])
+	}
+	if pattern == "" {
+		flags.Usage()
+		return 2
+	}
+
+	var words []string
+	if *wordlist != "" {
+		var err error
+		words, err = internal.LoadWordList(*wordlist)
+		if err != nil {
+			return fail(err)
+		}
+	}
+	if err := internal.PrintSolve(os.Stdout, pattern, *wrong, words, *top); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Let a solver strategy play lots of games and report how it did
+func bench(args []string) int {
+	flags := flag.NewFlagSet("hangman bench", flag.ExitOnError)
+	strategy := flags.String("strategy", "entropy", "how to pick letters: "+strings.Join(game.StrategyNames, ", "))
+	games := flags.Int("games", 1000, "how many games to play")
+	seed := flags.Int64("seed", 0, "seed for picking words and for the random strategy, 0 for a random one")
+	workers := flags.Int("workers", 0, "how many games to play at once, 0 for one per CPU")
+	difficulty := flags.String("difficulty", game.Medium.Name, "which difficulty preset to play: easy, medium, hard or expert")
+	wordlist := flags.String("wordlist", "", "play words from this file or directory instead of the dictionary")
+	format := flags.String("format", "table", "output format: table or csv (one row per game)")
+	hardest := flags.Int("hardest", 10, "how many of the hardest words to list in the table")
+	flags.Parse(args)
+
+	d, err := game.ParseDifficulty(*difficulty)
+	if err != nil {
+		return fail(err)
+	}
+	if *format != "table" && *format != "csv" {
+		return fail(fmt.Errorf("unknown format %q (choose from table, csv)", *format))
+	}
+	if *games < 1 {
+		return fail(fmt.Errorf("--games must be at least 1"))
+	}
+	if *hardest < 0 {
+		return fail(fmt.Errorf("--hardest can't be negative"))
+	}
+	var words []string
+	if *wordlist != "" {
+		words, err = internal.LoadWordList(*wordlist)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	err = internal.RunBench(os.Stdout, internal.BenchOptions{
+		Strategy:   *strategy,
+		Games:      *games,
+		Seed:       pickSeed(*seed),
+		Workers:    *workers,
+		Difficulty: d,
+		Words:      words,
+		CSV:        *format == "csv",
+		Hardest:    *hardest,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Host a game for other players to join
+func serve(args []string) int {
+	flags := flag.NewFlagSet("hangman serve", flag.ExitOnError)
+	addr := flags.String("addr", ":7777", "address to listen on")
+	mode := flags.String("mode", "turns", "how players guess: turns (one after another) or coop (anyone, any time)")
+	difficulty := flags.String("difficulty", game.Medium.Name, "how hard the game is: easy, medium, hard or expert")
+	category := flags.String("category", "", "play words from a word pack, like animals or movies")
+	wordlist := flags.String("wordlist", "", "play words from this file or directory instead of the dictionary")
+	seed := flags.Int64("seed", 0, "seed for picking words, 0 for a random one")
+	flags.Parse(args)
+
+	m, err := internal.ParseTurnMode(*mode)
+	if err != nil {
+		return fail(err)
+	}
+	d, err := game.ParseDifficulty(*difficulty)
+	if err != nil {
+		return fail(err)
+	}
+	if *category != "" && *wordlist != "" {
+		return fail(fmt.Errorf("pick either --category or --wordlist, not both"))
+	}
+	var words []string
+	if *category != "" {
+		packs, err := internal.LoadPacks()
+		if err != nil {
+			return fail(err)
+		}
+		pack, err := internal.FindPack(packs, *category)
+		if err != nil {
+			return fail(err)
+		}
+		words = pack.Words
+	}
+	if *wordlist != "" {
+		words, err = internal.LoadWordList(*wordlist)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	server := internal.NewServer(internal.ServerOptions{
+		Mode:       m,
+		Difficulty: d,
+		Words:      words,
+		Seed:       pickSeed(*seed),
+		Log:        log.New(os.Stderr, "", log.LstdFlags),
+	})
+	if err := server.ListenAndServe(*addr); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Play on someone's server
+func join(args []string) int {
+	flags := flag.NewFlagSet("hangman join", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: hangman join HOST:PORT [flags]")
+		flags.PrintDefaults()
+	}
+	name := flags.String("name", os.Getenv("USER"), "what to call you in the game")
+	theme := flags.String("theme", internal.DefaultThemeName, "color theme: a built-in Catppuccin flavor or a file in the themes directory")
+	flags.Parse(args)
+	// Flags can come after the address too
+	addr := flags.Arg(0)
+	if flags.NArg() > 1 {
+		flags.Parse(flags.Args()[1:])
+	}
+	if addr == "" {
+		flags.Usage()
+		return 2
+	}
+
+	t, err := internal.LoadTheme(*theme)
+	if err != nil {
+		return fail(err)
+	}
+	internal.ApplyTheme(t)
+	if err := internal.Join(addr, *name); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Watch a game saved with --replay-dir
+func replay(args []string) int {
+	flags := flag.NewFlagSet("hangman replay", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: hangman replay FILE [flags]")
+		flags.PrintDefaults()
+	}
+	theme := flags.String("theme", internal.DefaultThemeName, "color theme: a built-in Catppuccin flavor or a file in the themes directory")
+	flags.Parse(args)
+	// Flags can come after the file too
+	path := flags.Arg(0)
+	if flags.NArg() > 1 {
+		flags.Parse(flags.Args()[1:])
+	}
+	if path == "" {
+		flags.Usage()
+		return 2
+	}
+
+	t, err := internal.LoadTheme(*theme)
+	if err != nil {
+		return fail(err)
+	}
+	internal.ApplyTheme(t)
+	if err := internal.RunReplay(path); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Host the game over SSH
+func sshServe(args []string) int {
+	flags := flag.NewFlagSet("hangman ssh", flag.ExitOnError)
+	port := flags.Int("port", 2222, "port to listen on")
+	hostKey := flags.String("host-key", filepath.Join(internal.DefaultSSHDir(), "host_ed25519"), "the server's private key, made if it doesn't exist")
+	dataDir := flags.String("data-dir", internal.DefaultSSHDir(), "where to keep each player's stats and the high scores")
+	difficulty := flags.String("difficulty", game.Medium.Name, "how hard the game starts: easy, medium, hard or expert")
+	theme := flags.String("theme", internal.DefaultThemeName, "color theme: a built-in Catppuccin flavor or a file in the themes directory")
+	flags.Parse(args)
+
+	d, err := game.ParseDifficulty(*difficulty)
+	if err != nil {
+		return fail(err)
+	}
+	t, err := internal.LoadTheme(*theme)
+	if err != nil {
+		return fail(err)
+	}
+	packs, err := internal.LoadPacks()
+	if err != nil {
+		return fail(err)
+	}
+
+	err = internal.RunSSH(internal.SSHOptions{
+		Port:        *port,
+		HostKeyPath: *hostKey,
+		DataDir:     *dataDir,
+		Game: internal.Options{
+			Difficulty: d,
+			Packs:      packs,
+			StartMenu:  true,
+			Theme:      t,
+		},
+		Log: log.New(os.Stderr, "", log.LstdFlags),
+	})
+	if err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// Serve games over HTTP
+func api(args []string) int {
+	flags := flag.NewFlagSet("hangman api", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	ttl := flags.Duration("ttl", time.Hour, "forget games nobody has guessed in for this long, 0 to keep them forever")
+	flags.Parse(args)
+
+	if *ttl < 0 || (*ttl > 0 && *ttl < time.Second) {
+		return fail(fmt.Errorf("--ttl must be at least 1s, or 0 to keep games forever"))
+	}
+
+	packs, err := internal.LoadPacks()
+	if err != nil {
+		return fail(err)
+	}
+	err = internal.RunAPI(*addr, internal.APIOptions{
+		TTL:   *ttl,
+		Packs: packs,
+		Log:   log.New(os.Stderr, "", log.LstdFlags),
+	})
+	if err != nil {
+		return fail(err)
+	}
+	return 0
 }